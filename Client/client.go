@@ -2,69 +2,211 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"net"
 	"os"
 	"strconv"
 	"strings"
-	"time"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
 )
 
 func main() {
-	conn, err := net.Dial("tcp", "localhost:8080")
+	addr := flag.String("addr", "localhost:8080", "address of the battle server")
+	flag.Parse()
+
+	conn, err := net.Dial("tcp", *addr)
 	if err != nil {
 		fmt.Println("Error connecting:", err.Error())
 		return
 	}
+	server := protocol.NewConn(conn)
 	reader := bufio.NewReader(os.Stdin)
-	defer conn.Close()
+	defer server.Close()
 
 	fmt.Print("Enter your name: ")
 	name, _ := reader.ReadString('\n')
 	name = strings.TrimSpace(name)
-	conn.Write([]byte("Player name: " + name))
+	fmt.Print("Enter your password (an account without one yet takes this one): ")
+	password, _ := reader.ReadString('\n')
+	login := protocol.Login{Name: name, Password: strings.TrimSpace(password)}
+	server.Send(protocol.TypeLogin, login)
 
 	go func() {
+		finished := false
 		for {
-			buffer := make([]byte, 1024)
-			n, err := conn.Read(buffer)
+			msg, err := server.Receive()
 			if err != nil {
+				if finished {
+					os.Exit(0)
+				}
 				fmt.Println("Error reading from server:", err.Error())
 				os.Exit(1)
 			}
-			message := string(buffer[:n])
-			fmt.Println(message)
+			if msg.Type == protocol.TypeResult {
+				finished = true
+			}
+			printMessage(msg)
 		}
 	}()
+	fmt.Println("New players can enter 'register' to create an account.")
+	fmt.Println("Enter 'add {pokemon name or ID}' or 'remove {pokemon ID}' to change your roster.")
 	fmt.Print("Enter 'ready' when you are ready: ")
-	var clientChoice []string
+	var clientChoice []int
 	for {
 		message, _ := reader.ReadString('\n')
-		switch strings.TrimSpace(message) {
-		case "ready":
-			pokemonList := strings.Join(clientChoice, ",")
-			conn.Write([]byte("Player choice: " + pokemonList))
-			time.Sleep(3 * time.Second)
-			fmt.Println("You are ready. Please wait for the match to start")
-			conn.Write([]byte("ready"))
-		case "Attack":
-			conn.Write([]byte("Attack"))
-			fmt.Println("Sent 'Attack' to server")
-		default:
-			if isInteger(message) {
-				fmt.Println(len(clientChoice))
-				if len(clientChoice) < 3 {
-					clientChoice = append(clientChoice, strings.TrimSpace(message))
-				} else {
-					fmt.Println("You have chosen all pokemons. Please enter 'ready'")
-				}
-				fmt.Println(message)
+		message = strings.TrimSpace(message)
+		switch {
+		case message == "register":
+			server.Send(protocol.TypeRegister, login)
+		case strings.HasPrefix(message, "add ") || strings.HasPrefix(message, "remove "):
+			msgType, change := parseRosterChange(message)
+			server.Send(msgType, change)
+		case message == "ready":
+			// The server says whether it accepted the team. If it didn't, the
+			// player picks again from scratch.
+			server.Send(protocol.TypeChoose, protocol.Choose{PokemonIDs: clientChoice})
+			server.Send(protocol.TypeReady, nil)
+			clientChoice = nil
+		case message == "evolve" || message == "cancel":
+			server.Send(protocol.TypeEvolve, protocol.Evolve{Cancel: message == "cancel"})
+		case isInteger(message):
+			if len(clientChoice) < pokedata.MaxTeamSize {
+				choice, _ := strconv.Atoi(message)
+				clientChoice = append(clientChoice, choice)
 			} else {
-				fmt.Println(message)
-				message = strings.TrimSpace(message)
-				conn.Write([]byte(message))
+				fmt.Println("You have chosen all pokemons. Please enter 'ready'")
 			}
+			fmt.Println(message)
+		default:
+			action, err := parseAction(message)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			server.Send(protocol.TypeAction, action)
+		}
+	}
+}
+
+// parseRosterChange turns "add <name or ID>" or "remove <ID>" into a roster
+// change message.
+func parseRosterChange(input string) (string, protocol.RosterChange) {
+	command, target, _ := strings.Cut(input, " ")
+	target = strings.TrimSpace(target)
+
+	msgType := protocol.TypeAddPokemon
+	if command == "remove" {
+		msgType = protocol.TypeRemovePokemon
+	}
+	if id, err := strconv.Atoi(target); err == nil {
+		return msgType, protocol.RosterChange{ID: id}
+	}
+	return msgType, protocol.RosterChange{Name: target}
+}
+
+// parseAction turns "move <n>", "use <move name>", "switch" or "forfeit" into
+// an action message.
+func parseAction(input string) (protocol.Action, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return protocol.Action{}, errors.New("Enter an action")
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "move":
+		if len(fields) < 2 || !isInteger(fields[1]) {
+			return protocol.Action{}, errors.New("Usage: move {number}")
+		}
+		n, _ := strconv.Atoi(fields[1])
+		return protocol.Action{Kind: protocol.ActionMove, Move: n}, nil
+	case "use":
+		if len(fields) < 2 {
+			return protocol.Action{}, errors.New("Usage: use {move name}")
+		}
+		return protocol.Action{Kind: protocol.ActionMove, MoveName: strings.Join(fields[1:], " ")}, nil
+	case "switch":
+		return protocol.Action{Kind: protocol.ActionSwitch}, nil
+	case "forfeit":
+		return protocol.Action{Kind: protocol.ActionForfeit}, nil
+	}
+	return protocol.Action{}, fmt.Errorf("Unknown action: %s", input)
+}
+
+func printMessage(msg protocol.Message) {
+	switch msg.Type {
+	case protocol.TypeRoster:
+		var roster protocol.Roster
+		if err := msg.Decode(&roster); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("\nChoose your pokemons:")
+		for _, pokemon := range roster.Pokemons {
+			fmt.Printf("%d. %s Lv. %d\n", pokemon.ID, pokemon.Name, pokemon.Level)
+		}
+	case protocol.TypePrompt:
+		var prompt protocol.Prompt
+		if err := msg.Decode(&prompt); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if prompt.Status != "" {
+			fmt.Printf("%s Lv. %d (HP %d/%d, %s). Choose an action:\n", prompt.Pokemon, prompt.Level, prompt.HP, prompt.MaxHP, prompt.Status)
+		} else {
+			fmt.Printf("%s Lv. %d (HP %d/%d). Choose an action:\n", prompt.Pokemon, prompt.Level, prompt.HP, prompt.MaxHP)
+		}
+		fmt.Println("Moves:")
+		for _, move := range prompt.Moves {
+			fmt.Printf("  %d. %s (%s) PP %d/%d\n", move.Slot, move.Name, move.Type, move.PP, move.MaxPP)
+		}
+		fmt.Println("Move: move {number} or use {move name}")
+		if prompt.CanSwitch {
+			fmt.Println("Switch")
+		}
+		fmt.Println("Forfeit")
+	case protocol.TypeEvent:
+		var event protocol.Event
+		if err := msg.Decode(&event); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(event.Text)
+	case protocol.TypeResult:
+		var result protocol.Result
+		if err := msg.Decode(&result); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if result.Outcome == protocol.OutcomeWin {
+			fmt.Println(result.Reason, "You win!")
+		} else {
+			fmt.Println(result.Reason, "You lose!")
+		}
+	case protocol.TypeEvolution:
+		var evolution protocol.Evolution
+		if err := msg.Decode(&evolution); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("What? %s is evolving into %s!\n", evolution.From, evolution.To)
+		if evolution.Timeout > 0 {
+			fmt.Printf("Enter 'evolve' within %d seconds to continue or 'cancel' to stop the evolution.\n", evolution.Timeout)
+		} else {
+			fmt.Println("Enter 'evolve' to continue or 'cancel' to stop the evolution.")
+		}
+	case protocol.TypeError:
+		var serverErr protocol.Error
+		if err := msg.Decode(&serverErr); err != nil {
+			fmt.Println(err)
+			return
 		}
+		fmt.Println("Error:", serverErr.Message)
+	default:
+		fmt.Printf("Unknown message from server: %s\n", msg.Type)
 	}
 }
 
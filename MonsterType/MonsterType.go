@@ -1,71 +1,21 @@
 package MonsterType
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
-	"os"
-	"strings"
 
-	"github.com/PuerkitoBio/goquery"
+	"Pokemon/crawl"
 )
 
-type PokemonInfo struct {
-	ID           int           `json:"id"`
-	MonsterTypes []MonsterType `json:"monster_types"`
-}
-
-type MonsterType struct {
-	Type       string `json:"type"`
-	Multiplier string `json:"multiplier"`
-}
-
-func Crawl() {
-	pokemonInfos := make([]PokemonInfo, 0)
-
-	for i := 1; i <= 649; i++ {
-		url := fmt.Sprintf("https://pokedex.org/#/pokemon/%d", i)
-		doc, err := goquery.NewDocument(url)
-		if err != nil {
-			log.Fatalf("Error fetching URL %s: %v", url, err)
-		}
+// Source is the name the crawler command selects this crawler by.
+const Source = "monstertype"
 
-		monsterTypes := make([]MonsterType, 0)
-
-		doc.Find(".when-attacked-row").Each(func(i int, s *goquery.Selection) {
-			s.Find(".monster-type").Each(func(i int, s *goquery.Selection) {
-				mType := strings.TrimSpace(s.Text())
-				multiplier := strings.TrimSpace(s.Next().Text())
-				monsterTypes = append(monsterTypes, MonsterType{
-					Type:       mType,
-					Multiplier: multiplier,
-				})
-			})
-		})
-
-		pokemonInfo := PokemonInfo{
-			ID:           i,
-			MonsterTypes: monsterTypes,
-		}
-
-		pokemonInfos = append(pokemonInfos, pokemonInfo)
-	}
-
-	jsonData, err := json.MarshalIndent(pokemonInfos, "", "")
-	if err != nil {
-		log.Fatalf("Error marshaling JSON: %v", err)
-	}
-
-	file, err := os.Create("data/MonsterType.json")
+// Crawl downloads the multipliers each species takes when attacked from the
+// source and saves them to the output directory.
+func Crawl(ctx context.Context, opts crawl.Options) (crawl.Result, error) {
+	records, err := opts.Source.TypeMultipliers(ctx)
 	if err != nil {
-		log.Fatalf("Error creating file: %v", err)
+		return crawl.Result{}, fmt.Errorf("Failed to download the type multipliers: %w", err)
 	}
-	defer file.Close()
-
-	_, err = file.Write(jsonData)
-	if err != nil {
-		log.Fatalf("Error writing to file: %v", err)
-	}
-
-	fmt.Println("All info have been saved to MonsterType.json.")
+	return crawl.Save(opts, Source, "MonsterType", records)
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"Pokemon/pokedata"
+)
+
+type api struct {
+	data *pokedata.Dataset
+}
+
+func newHandler(data *pokedata.Dataset) http.Handler {
+	a := &api{data: data}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /pokemon/{key}", a.pokemon)
+	mux.HandleFunc("GET /moves/{id}", a.move)
+	mux.HandleFunc("GET /types/{type}/weaknesses", a.weaknesses)
+	mux.HandleFunc("GET /evolutions/{id}", a.evolution)
+	return mux
+}
+
+// pokemon serves everything known about a species, looked up by national ID
+// or name.
+func (a *api) pokemon(w http.ResponseWriter, r *http.Request) {
+	info, ok := a.info(r.PathValue("key"))
+	if !ok {
+		a.pokemonNotFound(w, r.PathValue("key"))
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (a *api) move(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "move ID must be a number")
+		return
+	}
+	move, ok := a.data.Moves[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown move %d", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, move)
+}
+
+type evolutionResponse struct {
+	NationalID int                        `json:"national_id"`
+	Name       string                     `json:"name"`
+	From       []pokedata.EvolutionDetail `json:"from"`
+	To         []pokedata.EvolutionDetail `json:"to"`
+}
+
+func (a *api) evolution(w http.ResponseWriter, r *http.Request) {
+	info, ok := a.info(r.PathValue("id"))
+	if !ok {
+		a.pokemonNotFound(w, r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, evolutionResponse{
+		NationalID: info.Pokemon.NationalID,
+		Name:       info.Pokemon.Name,
+		From:       info.Evolution.From,
+		To:         info.Evolution.To,
+	})
+}
+
+type weakness struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+type weaknessResponse struct {
+	Type       string     `json:"type"`
+	Weaknesses []weakness `json:"weaknesses"`
+}
+
+// weaknesses serves the attacking types that are super effective against a
+// type, read from the type chart.
+func (a *api) weaknesses(w http.ResponseWriter, r *http.Request) {
+	typeName := strings.ToLower(r.PathValue("type"))
+	types := a.data.TypeNames()
+	if i := sort.SearchStrings(types, typeName); i == len(types) || types[i] != typeName {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown type %q", typeName))
+		return
+	}
+
+	response := weaknessResponse{Type: typeName, Weaknesses: []weakness{}}
+	for _, attackType := range types {
+		if value := a.data.Effectiveness(attackType, typeName); value > 1 {
+			response.Weaknesses = append(response.Weaknesses, weakness{Type: attackType, Multiplier: value})
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (a *api) info(key string) (pokedata.PokemonInfo, bool) {
+	pokemon, ok := a.data.Find(key)
+	if !ok {
+		return pokedata.PokemonInfo{}, false
+	}
+	return a.data.Info(pokemon.NationalID)
+}
+
+type notFoundResponse struct {
+	Error       string   `json:"error"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// pokemonNotFound reports an unknown Pokémon along with the names the client
+// may have meant.
+func (a *api) pokemonNotFound(w http.ResponseWriter, key string) {
+	suggestions := a.data.SuggestPokemon(key, 5)
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+	writeJSON(w, http.StatusNotFound, notFoundResponse{
+		Error:       fmt.Sprintf("unknown Pokémon %q", key),
+		Suggestions: suggestions,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Println("Error writing response:", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
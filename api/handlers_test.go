@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"Pokemon/pokedata"
+)
+
+var (
+	loadOnce sync.Once
+	loaded   *pokedata.Dataset
+	loadErr  error
+)
+
+// testHandler serves the shipped data set, loaded once for all tests.
+func testHandler(t *testing.T) http.Handler {
+	t.Helper()
+	loadOnce.Do(func() {
+		loaded, loadErr = pokedata.Load("../data")
+	})
+	if loadErr != nil {
+		t.Fatalf("load data: %v", loadErr)
+	}
+	return newHandler(loaded)
+}
+
+func get(t *testing.T, path string) (int, map[string]interface{}) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	testHandler(t).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("GET %s: Content-Type = %q, want application/json", path, got)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: decode %q: %v", path, recorder.Body.String(), err)
+	}
+	return recorder.Code, body
+}
+
+func TestHandlerStatus(t *testing.T) {
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/pokemon/1", http.StatusOK},
+		{"/pokemon/bulbasaur", http.StatusOK},
+		{"/pokemon/Bulbasaur", http.StatusOK},
+		{"/pokemon/0", http.StatusNotFound},
+		{"/pokemon/missingno", http.StatusNotFound},
+		{"/moves/1", http.StatusOK},
+		{"/moves/pound", http.StatusBadRequest},
+		{"/moves/99999", http.StatusNotFound},
+		{"/types/fire/weaknesses", http.StatusOK},
+		{"/types/Fire/weaknesses", http.StatusOK},
+		{"/types/cosmic/weaknesses", http.StatusNotFound},
+		{"/evolutions/1", http.StatusOK},
+		{"/evolutions/ivysaur", http.StatusOK},
+		{"/evolutions/99999", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		code, body := get(t, tt.path)
+		if code != tt.want {
+			t.Errorf("GET %s = %d, want %d (body %v)", tt.path, code, tt.want, body)
+		}
+		if _, hasError := body["error"]; hasError != (tt.want != http.StatusOK) {
+			t.Errorf("GET %s: error field present = %v, want %v", tt.path, hasError, tt.want != http.StatusOK)
+		}
+	}
+}
+
+func TestHandlerPokemon(t *testing.T) {
+	_, body := get(t, "/pokemon/bulbasaur")
+	pokemon, _ := body["pokemon"].(map[string]interface{})
+	if pokemon["name"] != "Bulbasaur" || pokemon["national_id"] != 1.0 {
+		t.Errorf("pokemon = %v, want Bulbasaur #1", pokemon)
+	}
+}
+
+func TestHandlerMove(t *testing.T) {
+	_, body := get(t, "/moves/1")
+	if body["identifier"] != "pound" {
+		t.Errorf("move 1 = %v, want pound", body)
+	}
+}
+
+func TestHandlerWeaknesses(t *testing.T) {
+	_, body := get(t, "/types/fire/weaknesses")
+	if body["type"] != "fire" {
+		t.Errorf("type = %v, want fire", body["type"])
+	}
+
+	weaknesses, _ := body["weaknesses"].([]interface{})
+	if len(weaknesses) == 0 {
+		t.Fatal("no weaknesses for fire")
+	}
+	got := make(map[string]interface{})
+	previous := ""
+	for _, w := range weaknesses {
+		w := w.(map[string]interface{})
+		attacker := w["type"].(string)
+		if attacker <= previous {
+			t.Errorf("weaknesses not sorted by type: %q after %q", attacker, previous)
+		}
+		if multiplier, _ := w["multiplier"].(float64); multiplier <= 1 {
+			t.Errorf("weakness to %s has multiplier %v, want > 1", attacker, multiplier)
+		}
+		got[attacker] = w["multiplier"]
+		previous = attacker
+	}
+	want := map[string]interface{}{"ground": 2.0, "rock": 2.0, "water": 2.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fire weaknesses = %v, want %v", got, want)
+	}
+}
+
+func TestHandlerEvolution(t *testing.T) {
+	_, body := get(t, "/evolutions/ivysaur")
+	if body["name"] != "Ivysaur" {
+		t.Errorf("name = %v, want Ivysaur", body["name"])
+	}
+	from, _ := body["from"].([]interface{})
+	to, _ := body["to"].([]interface{})
+	if len(from) != 1 || from[0].(map[string]interface{})["name"] != "Bulbasaur" {
+		t.Errorf("from = %v, want Bulbasaur", from)
+	}
+	if len(to) != 1 || to[0].(map[string]interface{})["name"] != "Venusaur" {
+		t.Errorf("to = %v, want Venusaur", to)
+	}
+}
+
+func TestHandlerSuggestions(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/pokemon/bulbasuar", "Bulbasaur"},
+		{"/pokemon/CHARMANDR", "Charmander"},
+		{"/evolutions/pikachoo", "Pikachu"},
+	}
+	for _, tt := range tests {
+		code, body := get(t, tt.path)
+		if code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want %d", tt.path, code, http.StatusNotFound)
+		}
+		suggestions, _ := body["suggestions"].([]interface{})
+		if len(suggestions) == 0 || suggestions[0] != tt.want {
+			t.Errorf("GET %s: suggestions = %v, want %s first", tt.path, suggestions, tt.want)
+		}
+	}
+
+	// Suggestions are always a list, even when nothing is close.
+	_, body := get(t, "/pokemon/zzzzzzzzzz")
+	if suggestions, ok := body["suggestions"].([]interface{}); !ok || len(suggestions) != 0 {
+		t.Errorf("suggestions for nonsense = %v, want []", body["suggestions"])
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"Pokemon/pokedata"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	dataDir := flag.String("data", "../data", "directory containing the crawled data files")
+	flag.Parse()
+
+	data, err := pokedata.Load(*dataDir)
+	if err != nil {
+		fmt.Println("Error loading Pokémon data:", err)
+		return
+	}
+
+	fmt.Println("Pokédex API listening on", *addr)
+	if err := http.ListenAndServe(*addr, newHandler(data)); err != nil {
+		fmt.Println("Error serving:", err)
+	}
+}
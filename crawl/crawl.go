@@ -0,0 +1,91 @@
+// Package crawl holds what the crawler packages share: the options they are
+// run with, the result they report and how they write their output.
+package crawl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"Pokemon/source"
+)
+
+// Output formats. pokedata only reads FormatJSON.
+const (
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+)
+
+// Options tell a crawler where to read from and where to write.
+type Options struct {
+	// Source is where the records are read from.
+	Source source.Source
+	// OutDir is the directory the data files are written to.
+	OutDir string
+	// Format is FormatJSON for one indented array, or FormatJSONL for one
+	// record per line.
+	Format string
+	// DryRun fetches and parses everything but writes nothing.
+	DryRun bool
+}
+
+// Result is what a crawler fetched.
+type Result struct {
+	Source  string `json:"source"`
+	Records int    `json:"records"`
+	File    string `json:"file"`
+	Written bool   `json:"written"`
+}
+
+// CheckFormat reports whether format is one Save can write.
+func CheckFormat(format string) error {
+	if format != FormatJSON && format != FormatJSONL {
+		return fmt.Errorf("unknown format %q, want %s or %s", format, FormatJSON, FormatJSONL)
+	}
+	return nil
+}
+
+// Save writes the records of a source to name in the output directory, with
+// the extension of the format, unless it's a dry run.
+func Save[T any](opts Options, source, name string, records []T) (Result, error) {
+	if err := CheckFormat(opts.Format); err != nil {
+		return Result{}, err
+	}
+	result := Result{
+		Source:  source,
+		Records: len(records),
+		File:    filepath.Join(opts.OutDir, name+"."+opts.Format),
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	var data []byte
+	switch opts.Format {
+	case FormatJSON:
+		var err error
+		if data, err = json.MarshalIndent(records, "", "  "); err != nil {
+			return result, fmt.Errorf("Failed to marshal %s to JSON: %w", source, err)
+		}
+	case FormatJSONL:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return result, fmt.Errorf("Failed to marshal %s to JSON: %w", source, err)
+			}
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(opts.OutDir, 0755); err != nil {
+		return result, fmt.Errorf("Failed to create %s: %w", opts.OutDir, err)
+	}
+	if err := os.WriteFile(result.File, data, 0644); err != nil {
+		return result, fmt.Errorf("Failed to write %s to file: %s: %w", source, result.File, err)
+	}
+	result.Written = true
+	return result, nil
+}
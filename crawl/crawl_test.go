@@ -0,0 +1,67 @@
+package crawl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type record struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+var records = []record{{"Bulbasaur", 1}, {"Ivysaur", 2}}
+
+func TestSave(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatJSON, "[\n  {\n    \"name\": \"Bulbasaur\",\n    \"id\": 1\n  },\n  {\n    \"name\": \"Ivysaur\",\n    \"id\": 2\n  }\n]"},
+		{FormatJSONL, "{\"name\":\"Bulbasaur\",\"id\":1}\n{\"name\":\"Ivysaur\",\"id\":2}\n"},
+	}
+	for _, tt := range tests {
+		// The output directory is created if it's missing.
+		dir := filepath.Join(t.TempDir(), "data")
+		result, err := Save(Options{OutDir: dir, Format: tt.format}, "baseinfo", "baseInfo", records)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		want := Result{Source: "baseinfo", Records: 2, File: filepath.Join(dir, "baseInfo."+tt.format), Written: true}
+		if result != want {
+			t.Errorf("%s: Result = %+v, want %+v", tt.format, result, want)
+		}
+		data, err := os.ReadFile(result.File)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s: wrote %q, want %q", tt.format, data, tt.want)
+		}
+	}
+}
+
+func TestSaveDryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	result, err := Save(Options{OutDir: dir, Format: FormatJSON, DryRun: true}, "moves", "moves", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Written || result.Records != 2 || result.File != filepath.Join(dir, "moves.json") {
+		t.Errorf("Result = %+v, want 2 records for moves.json, not written", result)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", dir)
+	}
+}
+
+func TestSaveUnknownFormat(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Save(Options{OutDir: dir, Format: "csv"}, "moves", "moves", records); err == nil {
+		t.Fatal("Save in csv succeeded, want an error")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Save in an unknown format wrote %v", entries)
+	}
+}
@@ -1,24 +1,168 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
 	"Pokemon/BaseInfo"
 	"Pokemon/MonsterMoves"
 	"Pokemon/MonsterType"
 	"Pokemon/Stats"
+	"Pokemon/crawl"
 	"Pokemon/description"
 	"Pokemon/evolution"
 	"Pokemon/exp"
+	"Pokemon/fetch"
 	"Pokemon/move"
+	"Pokemon/source"
 )
 
+type crawler struct {
+	source string
+	crawl  func(context.Context, crawl.Options) (crawl.Result, error)
+}
+
+// crawlers are run in this order.
+var crawlers = []crawler{
+	{BaseInfo.Source, BaseInfo.Crawl},
+	{move.Source, move.Crawl},
+	{MonsterMoves.Source, MonsterMoves.Crawl},
+	{Stats.Source, Stats.Crawl},
+	{evolution.Source, evolution.Crawl},
+	{description.Source, description.Crawl},
+	{MonsterType.Source, MonsterType.Crawl},
+	{exp.Source, exp.Crawl},
+}
+
 func main() {
-	BaseInfo.Crawl()
-	move.Crawl()
-	MonsterMoves.Crawl()
-	Stats.Crawl()
-	evolution.Crawl()
-	description.Crawl()
-	MonsterType.Crawl()
-	exp.Crawl()
-	exp.Remove()
+	config := fetch.DefaultConfig()
+	config.Hosts = make(map[string]string)
+	sourceName := flag.String("source", "pokedex.org", "where to read the data from: pokedex.org or pokeapi (pokedex.org has no type multipliers, so monstertype needs pokeapi)")
+	pokeapiURL := flag.String("pokeapi", source.DefaultPokeAPIURL, "base URL of the PokéAPI-compatible REST API for -source pokeapi")
+	limit := flag.Int("limit", 0, "how many species to read from -source pokeapi, or 0 for all")
+	versionGroup := flag.String("version-group", "", "version group to read the learnsets of from -source pokeapi (default the newest)")
+	only := flag.String("only", "", "comma-separated crawlers to run (default all): "+strings.Join(sourceNames(), ", "))
+	outDir := flag.String("out", "data", "directory to write the data files to")
+	format := flag.String("format", crawl.FormatJSON, "output format: json or jsonl")
+	dryRun := flag.Bool("dry-run", false, "fetch and parse everything but write nothing")
+	flag.StringVar(&config.CacheDir, "cache", ".cache/crawler", "directory to cache responses in, or empty for no cache")
+	flag.BoolVar(&config.Offline, "offline", false, "replay responses from the cache without using the network")
+	flag.IntVar(&config.Workers, "workers", config.Workers, "how many pages to download at once")
+	flag.Func("host", "send requests for a host to another base URL, as host=url (repeatable)", func(value string) error {
+		host, base, ok := strings.Cut(value, "=")
+		if !ok || host == "" || base == "" {
+			return fmt.Errorf("want host=url, got %q", value)
+		}
+		config.Hosts[host] = base
+		return nil
+	})
+	flag.Parse()
+
+	if config.Offline && config.CacheDir == "" {
+		log.Fatal("-offline needs a -cache directory to replay from")
+	}
+	if err := crawl.CheckFormat(*format); err != nil {
+		log.Fatal(err)
+	}
+	selected, err := selectCrawlers(*only)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var src source.Source
+	switch *sourceName {
+	case "pokedex.org":
+		src = source.NewPokedexOrg(fetch.New(config))
+	case "pokeapi":
+		src = source.NewPokeAPI(fetch.New(config), *pokeapiURL, *limit, *versionGroup)
+	default:
+		log.Fatalf("unknown source %q, want pokedex.org or pokeapi", *sourceName)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	opts := crawl.Options{
+		Source: src,
+		OutDir: *outDir,
+		Format: *format,
+		DryRun: *dryRun,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Source\tRecords\tFile\tTime")
+	failed := false
+	for _, c := range selected {
+		start := time.Now()
+		result, err := c.crawl(ctx, opts)
+		if errors.Is(err, errors.ErrUnsupported) {
+			fmt.Fprintf(w, "%s\t-\t(skipped: %s doesn't provide it)\t\n", c.source, src.Name())
+			continue
+		}
+		if err != nil {
+			log.Printf("%s: %v", c.source, err)
+			failed = true
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		file := result.File
+		if !result.Written {
+			file += " (not written)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", result.Source, result.Records, file, time.Since(start).Round(time.Millisecond))
+	}
+	w.Flush()
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func sourceNames() []string {
+	var names []string
+	for _, c := range crawlers {
+		names = append(names, c.source)
+	}
+	return names
+}
+
+// selectCrawlers returns the crawlers named in a comma-separated list, in the
+// order they're normally run, or all of them for an empty list.
+func selectCrawlers(only string) ([]crawler, error) {
+	if strings.TrimSpace(only) == "" {
+		return crawlers, nil
+	}
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, c := range crawlers {
+			if c.source == name {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown crawler %q, want one of %s", name, strings.Join(sourceNames(), ", "))
+		}
+		wanted[name] = true
+	}
+
+	var selected []crawler
+	for _, c := range crawlers {
+		if wanted[c.source] {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
 }
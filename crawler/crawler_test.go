@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func selectedSources(t *testing.T, only string) []string {
+	t.Helper()
+	selected, err := selectCrawlers(only)
+	if err != nil {
+		t.Fatalf("selectCrawlers(%q): %v", only, err)
+	}
+	var sources []string
+	for _, c := range selected {
+		sources = append(sources, c.source)
+	}
+	return sources
+}
+
+func TestSelectCrawlers(t *testing.T) {
+	tests := []struct {
+		only string
+		want []string
+	}{
+		{"", sourceNames()},
+		{"  ", sourceNames()},
+		{"moves", []string{"moves"}},
+		// Crawlers run in their usual order whatever order they're listed in.
+		{"exp,baseinfo", []string{"baseinfo", "exp"}},
+		{" Moves , STATS ", []string{"moves", "stats"}},
+		{"moves,,moves", []string{"moves"}},
+	}
+	for _, tt := range tests {
+		if got := selectedSources(t, tt.only); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("selectCrawlers(%q) = %v, want %v", tt.only, got, tt.want)
+		}
+	}
+}
+
+func TestSelectCrawlersUnknown(t *testing.T) {
+	for _, only := range []string{"pokemon", "moves,pokemon"} {
+		if selected, err := selectCrawlers(only); err == nil {
+			t.Errorf("selectCrawlers(%q) = %d crawlers, want an error", only, len(selected))
+		}
+	}
+}
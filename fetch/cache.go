@@ -0,0 +1,110 @@
+package fetch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotCached is returned in offline mode for pages that were never
+// downloaded.
+var ErrNotCached = errors.New("not in the cache")
+
+// cacheEntry describes a cached response. The body is stored next to it.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// cache keeps responses on disk, keyed by URL, so they can be revalidated
+// instead of downloaded again, or replayed without a network.
+type cache struct {
+	dir string
+}
+
+func (c *cache) paths(rawURL string) (meta, body string) {
+	sum := sha256.Sum256([]byte(rawURL))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key+".json"), filepath.Join(c.dir, key+".body")
+}
+
+// load returns the cached response for a URL, if there is one.
+func (c *cache) load(rawURL string) (cacheEntry, []byte, bool) {
+	metaPath, bodyPath := c.paths(rawURL)
+	metaData, err := os.ReadFile(metaPath)
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(metaData, &entry); err != nil || entry.URL != rawURL {
+		return cacheEntry{}, nil, false
+	}
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+	return entry, body, true
+}
+
+// store saves a response. The body is written before the entry describing
+// it so that an interrupted write never leaves an entry without its body.
+func (c *cache) store(rawURL string, header http.Header, body []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	entry := cacheEntry{
+		URL:          rawURL,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		FetchedAt:    time.Now().UTC(),
+	}
+	metaData, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	metaPath, bodyPath := c.paths(rawURL)
+	if err := writeFileAtomic(bodyPath, body); err != nil {
+		return err
+	}
+	return writeFileAtomic(metaPath, metaData)
+}
+
+// touch records that a cached response was revalidated.
+func (c *cache) touch(rawURL string, entry cacheEntry) error {
+	entry.FetchedAt = time.Now().UTC()
+	metaData, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	metaPath, _ := c.paths(rawURL)
+	return writeFileAtomic(metaPath, metaData)
+}
+
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// page is a resource whose body and validators a test can change between
+// requests.
+type page struct {
+	mu           sync.Mutex
+	body         string
+	etag         string
+	lastModified string
+	requests     int
+	// conditional counts the requests that carried a validator.
+	conditional int
+}
+
+func (p *page) set(body, etag, lastModified string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.body, p.etag, p.lastModified = body, etag, lastModified
+}
+
+func (p *page) counts() (requests, conditional int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests, p.conditional
+}
+
+func (p *page) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests++
+	inm, ims := r.Header.Get("If-None-Match"), r.Header.Get("If-Modified-Since")
+	if inm != "" || ims != "" {
+		p.conditional++
+	}
+	if (inm != "" && inm == p.etag) || (inm == "" && ims != "" && ims == p.lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if p.etag != "" {
+		w.Header().Set("ETag", p.etag)
+	}
+	if p.lastModified != "" {
+		w.Header().Set("Last-Modified", p.lastModified)
+	}
+	w.Write([]byte(p.body))
+}
+
+func newTestFetcher(dir string, offline bool) *Fetcher {
+	return New(Config{Retries: 0, CacheDir: dir, Offline: offline})
+}
+
+func get(t *testing.T, f *Fetcher, url string) string {
+	t.Helper()
+	body, err := f.Get(context.Background(), url)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", url, err)
+	}
+	return string(body)
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	tests := []struct {
+		name string
+		// etag and lastModified are the validators of the first response.
+		etag, lastModified string
+		// changed is how the page changes before the second request, if it
+		// does.
+		changed                  bool
+		newEtag, newLastModified string
+		want                     string
+	}{
+		{name: "etag not modified", etag: `"v1"`, want: "one"},
+		{name: "last-modified not modified", lastModified: "Mon, 01 Jan 2024 00:00:00 GMT", want: "one"},
+		{name: "etag changed", etag: `"v1"`, changed: true, newEtag: `"v2"`, want: "two"},
+		{name: "last-modified changed", lastModified: "Mon, 01 Jan 2024 00:00:00 GMT", changed: true,
+			newLastModified: "Tue, 02 Jan 2024 00:00:00 GMT", want: "two"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &page{}
+			p.set("one", tt.etag, tt.lastModified)
+			server := httptest.NewServer(p)
+			defer server.Close()
+			dir := t.TempDir()
+			f := newTestFetcher(dir, false)
+
+			if got := get(t, f, server.URL); got != "one" {
+				t.Fatalf("first Get = %q, want %q", got, "one")
+			}
+			if _, conditional := p.counts(); conditional != 0 {
+				t.Fatalf("first request carried a validator with an empty cache")
+			}
+
+			if tt.changed {
+				p.set("two", tt.newEtag, tt.newLastModified)
+			}
+			if got := get(t, f, server.URL); got != tt.want {
+				t.Errorf("second Get = %q, want %q", got, tt.want)
+			}
+			if requests, conditional := p.counts(); requests != 2 || conditional != 1 {
+				t.Errorf("server saw %d requests, %d conditional; want 2 and 1", requests, conditional)
+			}
+
+			// Whatever the second response was is what is replayed offline.
+			if got := get(t, newTestFetcher(dir, true), server.URL); got != tt.want {
+				t.Errorf("offline Get = %q, want %q", got, tt.want)
+			}
+			if requests, _ := p.counts(); requests != 2 {
+				t.Errorf("offline Get made a request")
+			}
+		})
+	}
+}
+
+func TestCacheWithoutValidators(t *testing.T) {
+	p := &page{}
+	p.set("one", "", "")
+	server := httptest.NewServer(p)
+	defer server.Close()
+	f := newTestFetcher(t.TempDir(), false)
+
+	get(t, f, server.URL)
+	p.set("two", "", "")
+	if got := get(t, f, server.URL); got != "two" {
+		t.Errorf("second Get = %q, want %q", got, "two")
+	}
+	if _, conditional := p.counts(); conditional != 0 {
+		t.Errorf("sent %d conditional requests without validators", conditional)
+	}
+}
+
+func TestOfflineMiss(t *testing.T) {
+	p := &page{}
+	p.set("one", `"v1"`, "")
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	for _, dir := range []string{"", t.TempDir()} {
+		_, err := newTestFetcher(dir, true).Get(context.Background(), server.URL)
+		if !errors.Is(err, ErrNotCached) {
+			t.Errorf("offline Get with cache %q: err = %v, want ErrNotCached", dir, err)
+		}
+	}
+	if requests, _ := p.counts(); requests != 0 {
+		t.Errorf("offline Get made %d requests", requests)
+	}
+}
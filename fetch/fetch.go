@@ -0,0 +1,357 @@
+// Package fetch downloads the pages the crawlers read, sharing a bounded pool
+// of workers, a rate limit per host, retries with exponential backoff and a
+// timeout per request between all of them. Responses can be kept in an
+// on-disk cache, revalidated with ETag and Last-Modified, and replayed
+// offline.
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config tunes a Fetcher. Zero values are replaced by those of DefaultConfig.
+type Config struct {
+	// Workers is how many requests GetAll makes at once.
+	Workers int
+	// Interval is the least time between two requests to the same host.
+	Interval time.Duration
+	// Retries is how many times a failed request is tried again.
+	Retries int
+	// Backoff is the wait before the first retry. It doubles on each retry.
+	Backoff time.Duration
+	// Timeout bounds each attempt, including reading the body.
+	Timeout time.Duration
+	// Header is sent with every request.
+	Header http.Header
+	// HostHeaders are sent, on top of Header, with requests to a given host.
+	HostHeaders map[string]http.Header
+	// Hosts sends the requests for a host to another base URL instead, such
+	// as "http://localhost:9000" for a local fixture server. Responses are
+	// still cached under the original URL.
+	Hosts map[string]string
+	// CacheDir is where responses are cached. There is no cache if it's empty.
+	CacheDir string
+	// Offline replays responses from the cache without touching the network.
+	Offline bool
+}
+
+// DefaultConfig returns the settings the crawlers use. pokedex.org only
+// serves its data files to requests that look like they come from its own
+// web worker.
+func DefaultConfig() Config {
+	return Config{
+		Workers:  4,
+		Interval: 200 * time.Millisecond,
+		Retries:  3,
+		Backoff:  500 * time.Millisecond,
+		Timeout:  30 * time.Second,
+		Header: http.Header{
+			"User-Agent": {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36"},
+		},
+		HostHeaders: map[string]http.Header{
+			"pokedex.org": {"Referer": {"https://pokedex.org/js/worker.js"}},
+		},
+	}
+}
+
+// Fetcher downloads pages. It is safe for concurrent use.
+type Fetcher struct {
+	config Config
+	client *http.Client
+	cache  *cache
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+// New returns a Fetcher with the given settings.
+func New(config Config) *Fetcher {
+	defaults := DefaultConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.Interval < 0 {
+		config.Interval = 0
+	}
+	if config.Retries < 0 {
+		config.Retries = 0
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = defaults.Backoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	f := &Fetcher{
+		config: config,
+		client: &http.Client{},
+		hosts:  make(map[string]*hostLimiter),
+	}
+	if config.CacheDir != "" {
+		f.cache = &cache{dir: config.CacheDir}
+	}
+	return f
+}
+
+// StatusError is returned for responses that aren't 200 OK.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	// RetryAfter is how long the server asked us to wait, if it did.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// temporary reports whether the request might succeed if it's tried again.
+func (e *StatusError) temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Get downloads one page, retrying network errors, rate limiting and server
+// errors with exponential backoff. In offline mode it is read from the cache.
+func (f *Fetcher) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	if f.config.Offline {
+		if f.cache != nil {
+			if _, body, ok := f.cache.load(rawURL); ok {
+				return body, nil
+			}
+		}
+		return nil, fmt.Errorf("GET %s: %w", rawURL, ErrNotCached)
+	}
+
+	backoff := f.config.Backoff
+	for attempt := 0; ; attempt++ {
+		body, err := f.get(ctx, rawURL)
+		if err == nil {
+			return body, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		wait := backoff
+		if statusErr, ok := err.(*StatusError); ok {
+			if !statusErr.temporary() {
+				return nil, err
+			}
+			if statusErr.RetryAfter > wait {
+				wait = statusErr.RetryAfter
+			}
+		}
+		if attempt >= f.config.Retries {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		}
+
+		log.Printf("Retrying %s in %s: %v", rawURL, wait, err)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// get makes a single attempt at downloading a page. A cached copy is
+// revalidated rather than downloaded again.
+func (f *Fetcher) get(ctx context.Context, rawURL string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := parsed.Hostname()
+	requestURL, err := f.rewrite(parsed)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.limiter(requestURL.Host).wait(ctx, f.config.Interval); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range f.config.Header {
+		req.Header[key] = values
+	}
+	for key, values := range f.config.HostHeaders[host] {
+		req.Header[key] = values
+	}
+
+	var (
+		cached     cacheEntry
+		cachedBody []byte
+		isCached   bool
+	)
+	if f.cache != nil {
+		cached, cachedBody, isCached = f.cache.load(rawURL)
+	}
+	if isCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && isCached {
+		if err := f.cache.touch(rawURL, cached); err != nil {
+			log.Printf("Failed to update the cache for %s: %v", rawURL, err)
+		}
+		return cachedBody, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		statusErr := &StatusError{URL: rawURL, StatusCode: resp.StatusCode}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, statusErr
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", rawURL, err)
+	}
+	if f.cache != nil {
+		if err := f.cache.store(rawURL, resp.Header, body); err != nil {
+			log.Printf("Failed to cache %s: %v", rawURL, err)
+		}
+	}
+	return body, nil
+}
+
+// rewrite points a URL at the replacement base URL of its host, if it has
+// one.
+func (f *Fetcher) rewrite(parsed *url.URL) (*url.URL, error) {
+	base, ok := f.config.Hosts[parsed.Hostname()]
+	if !ok {
+		return parsed, nil
+	}
+	target, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("base URL for %s: %w", parsed.Hostname(), err)
+	}
+	rewritten := *parsed
+	rewritten.Scheme = target.Scheme
+	rewritten.Host = target.Host
+	rewritten.Path = strings.TrimSuffix(target.Path, "/") + parsed.Path
+	rewritten.RawPath = ""
+	return &rewritten, nil
+}
+
+// GetAll downloads several pages with the worker pool, returning their bodies
+// in the same order as the URLs. The first page that can't be downloaded
+// cancels the rest.
+func (f *Fetcher) GetAll(ctx context.Context, urls []string) ([][]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	bodies := make([][]byte, len(urls))
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	workers := min(f.config.Workers, len(urls))
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				body, err := f.Get(ctx, urls[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				bodies[i] = body
+			}
+		}()
+	}
+
+feed:
+	for i := range urls {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return bodies, nil
+}
+
+func (f *Fetcher) limiter(host string) *hostLimiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	limiter, ok := f.hosts[host]
+	if !ok {
+		limiter = &hostLimiter{}
+		f.hosts[host] = limiter
+	}
+	return limiter
+}
+
+// hostLimiter spaces out the requests to one host.
+type hostLimiter struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next request to the host may be made.
+func (l *hostLimiter) wait(ctx context.Context, interval time.Duration) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(at))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,221 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flaky answers each request with the next status in its script, then 200
+// OK, and records when the requests arrived.
+type flaky struct {
+	mu         sync.Mutex
+	statuses   []int
+	retryAfter string
+	times      []time.Time
+	header     http.Header
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.times = append(f.times, time.Now())
+	f.header = r.Header.Clone()
+	if len(f.statuses) > 0 {
+		status := f.statuses[0]
+		f.statuses = f.statuses[1:]
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		w.WriteHeader(status)
+		return
+	}
+	w.Write([]byte("ok " + r.URL.Path))
+}
+
+func (f *flaky) requests() []time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Time(nil), f.times...)
+}
+
+func TestGetRetries(t *testing.T) {
+	handler := &flaky{statuses: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusInternalServerError}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	const backoff = 20 * time.Millisecond
+	body, err := New(Config{Retries: 3, Backoff: backoff}).Get(context.Background(), server.URL+"/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "ok /page" {
+		t.Errorf("body = %q, want %q", body, "ok /page")
+	}
+
+	times := handler.requests()
+	if len(times) != 4 {
+		t.Fatalf("made %d requests, want 4", len(times))
+	}
+	// The backoff doubles after every retry.
+	for i, want := range []time.Duration{backoff, 2 * backoff, 4 * backoff} {
+		if gap := times[i+1].Sub(times[i]); gap < want {
+			t.Errorf("retry %d came after %s, want at least %s", i+1, gap, want)
+		}
+	}
+}
+
+func TestGetGivesUp(t *testing.T) {
+	handler := &flaky{statuses: []int{500, 502, 503, 504}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	_, err := New(Config{Retries: 2, Backoff: time.Millisecond}).Get(context.Background(), server.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 503 {
+		t.Fatalf("Get = %v, want the last 503", err)
+	}
+	if n := len(handler.requests()); n != 3 {
+		t.Errorf("made %d requests, want 3", n)
+	}
+}
+
+func TestGetDoesNotRetryClientErrors(t *testing.T) {
+	handler := &flaky{statuses: []int{http.StatusNotFound}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	_, err := New(Config{Retries: 3, Backoff: time.Millisecond}).Get(context.Background(), server.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Get = %v, want a 404", err)
+	}
+	if n := len(handler.requests()); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestGetHonorsRetryAfter(t *testing.T) {
+	handler := &flaky{statuses: []int{http.StatusTooManyRequests}, retryAfter: "1"}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	if _, err := New(Config{Retries: 1, Backoff: time.Millisecond}).Get(context.Background(), server.URL); err != nil {
+		t.Fatal(err)
+	}
+	times := handler.requests()
+	if len(times) != 2 {
+		t.Fatalf("made %d requests, want 2", len(times))
+	}
+	if gap := times[1].Sub(times[0]); gap < time.Second {
+		t.Errorf("retried after %s, want at least the 1s the server asked for", gap)
+	}
+}
+
+func TestGetCanceled(t *testing.T) {
+	handler := &flaky{statuses: []int{500}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := New(Config{Retries: 3, Backoff: time.Hour}).Get(ctx, server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get = %v, want the context's error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Get waited %s after the context was done", elapsed)
+	}
+}
+
+func TestGetTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := New(Config{Timeout: 20 * time.Millisecond}).Get(context.Background(), server.URL); err == nil {
+		t.Fatal("Get of a page slower than the timeout succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get took %s, want it cut off by the timeout", elapsed)
+	}
+}
+
+func TestGetAllRateLimit(t *testing.T) {
+	handler := &flaky{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var urls []string
+	for i := 0; i < 5; i++ {
+		urls = append(urls, server.URL+"/"+strconv.Itoa(i))
+	}
+	const interval = 30 * time.Millisecond
+	bodies, err := New(Config{Workers: 5, Interval: interval}).GetAll(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, body := range bodies {
+		if want := "ok /" + strconv.Itoa(i); string(body) != want {
+			t.Errorf("body %d = %q, want %q", i, body, want)
+		}
+	}
+
+	// All the workers share one host, so their requests are spaced out.
+	times := handler.requests()
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	if total := times[len(times)-1].Sub(times[0]); total < 4*interval {
+		t.Errorf("5 requests took %s, want at least %s", total, 4*interval)
+	}
+}
+
+func TestGetAllStopsAtFirstError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	_, err := New(Config{Workers: 2}).GetAll(context.Background(), []string{server.URL + "/a", server.URL + "/missing", server.URL + "/b"})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetAll = %v, want the 404", err)
+	}
+}
+
+func TestGetHeaders(t *testing.T) {
+	handler := &flaky{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	fetcher := New(Config{
+		Header:      http.Header{"User-Agent": {"test-agent"}},
+		HostHeaders: map[string]http.Header{"127.0.0.1": {"Referer": {"https://example.org/"}}},
+	})
+	if _, err := fetcher.Get(context.Background(), server.URL); err != nil {
+		t.Fatal(err)
+	}
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if got := handler.header.Get("User-Agent"); got != "test-agent" {
+		t.Errorf("User-Agent = %q, want test-agent", got)
+	}
+	if got := handler.header.Get("Referer"); got != "https://example.org/" {
+		t.Errorf("Referer = %q, want the host's", got)
+	}
+}
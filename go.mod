@@ -2,7 +2,10 @@ module Pokemon
 
 go 1.22.1
 
-require github.com/PuerkitoBio/goquery v1.9.2
+require (
+	github.com/PuerkitoBio/goquery v1.9.2
+	golang.org/x/crypto v0.22.0
+)
 
 require (
 	github.com/andybalholm/cascadia v1.3.2 // indirect
@@ -0,0 +1,182 @@
+package pokedata
+
+import (
+	"sort"
+	"strings"
+)
+
+// BaseStats are a species' base stats and their sum.
+type BaseStats struct {
+	HP      int `json:"hp"`
+	Attack  int `json:"attack"`
+	Defense int `json:"defense"`
+	SpAtk   int `json:"sp_atk"`
+	SpDef   int `json:"sp_def"`
+	Speed   int `json:"speed"`
+	Total   int `json:"total"`
+}
+
+// BaseStats returns the species' base stats with their total.
+func (p *Pokemon) BaseStats() BaseStats {
+	total, _ := p.Stat("total")
+	return BaseStats{
+		HP:      p.HP,
+		Attack:  p.Attack,
+		Defense: p.Defense,
+		SpAtk:   p.SpAtk,
+		SpDef:   p.SpDef,
+		Speed:   p.Speed,
+		Total:   total,
+	}
+}
+
+func (s BaseStats) minus(other BaseStats) BaseStats {
+	return BaseStats{
+		HP:      s.HP - other.HP,
+		Attack:  s.Attack - other.Attack,
+		Defense: s.Defense - other.Defense,
+		SpAtk:   s.SpAtk - other.SpAtk,
+		SpDef:   s.SpDef - other.SpDef,
+		Speed:   s.Speed - other.Speed,
+		Total:   s.Total - other.Total,
+	}
+}
+
+// Comparison sets several species side by side.
+type Comparison struct {
+	Pokemon []ComparedPokemon `json:"pokemon"`
+	// SharedMoves can be learned by every compared species.
+	SharedMoves []string  `json:"shared_moves"`
+	Matchups    []Matchup `json:"matchups"`
+	// SpeedOrder lists the species fastest first.
+	SpeedOrder []string   `json:"speed_order"`
+	Outspeeds  []Outspeed `json:"outspeeds"`
+}
+
+type ComparedPokemon struct {
+	NationalID int       `json:"national_id"`
+	Name       string    `json:"name"`
+	Types      []string  `json:"types"`
+	Stats      BaseStats `json:"stats"`
+	// Deltas are the stats minus those of the first compared species.
+	Deltas BaseStats `json:"deltas"`
+	// ExclusiveMoves can be learned by this species and none of the others.
+	ExclusiveMoves []string `json:"exclusive_moves"`
+}
+
+// Matchup is how much damage one species' attacks of its own type deal to
+// another, per the type chart.
+type Matchup struct {
+	Attacker   string  `json:"attacker"`
+	Defender   string  `json:"defender"`
+	AttackType string  `json:"attack_type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Outspeed says which of two species moves first. Tie is set when their
+// speeds are equal and the order is decided at random.
+type Outspeed struct {
+	Faster string `json:"faster"`
+	Slower string `json:"slower"`
+	Tie    bool   `json:"tie,omitempty"`
+}
+
+// Compare builds a comparison of the species with the given national IDs.
+// Unknown IDs are skipped.
+func (d *Dataset) Compare(nationalIDs ...int) Comparison {
+	var infos []PokemonInfo
+	for _, id := range nationalIDs {
+		if info, ok := d.Info(id); ok {
+			infos = append(infos, info)
+		}
+	}
+
+	c := Comparison{
+		SharedMoves: []string{},
+		Matchups:    []Matchup{},
+		Outspeeds:   []Outspeed{},
+	}
+	learnsets := make([]map[string]bool, len(infos))
+	for i, info := range infos {
+		learnsets[i] = make(map[string]bool)
+		for _, move := range info.MonsterMoves.Moves {
+			if move.Details != nil {
+				learnsets[i][move.Details.Name] = true
+			}
+		}
+	}
+
+	for i, info := range infos {
+		compared := ComparedPokemon{
+			NationalID:     info.Pokemon.NationalID,
+			Name:           info.Pokemon.Name,
+			Stats:          info.Pokemon.BaseStats(),
+			ExclusiveMoves: []string{},
+		}
+		for _, t := range info.Pokemon.Types {
+			compared.Types = append(compared.Types, t.Name)
+		}
+		compared.Deltas = compared.Stats.minus(infos[0].Pokemon.BaseStats())
+
+		for move := range learnsets[i] {
+			learnedByOthers := 0
+			for j := range learnsets {
+				if j != i && learnsets[j][move] {
+					learnedByOthers++
+				}
+			}
+			switch {
+			case learnedByOthers == 0 && len(infos) > 1:
+				compared.ExclusiveMoves = append(compared.ExclusiveMoves, move)
+			case learnedByOthers == len(infos)-1 && i == 0:
+				c.SharedMoves = append(c.SharedMoves, move)
+			}
+		}
+		sort.Strings(compared.ExclusiveMoves)
+		c.Pokemon = append(c.Pokemon, compared)
+	}
+	sort.Strings(c.SharedMoves)
+
+	for i, attacker := range infos {
+		for j, defender := range infos {
+			if i == j {
+				continue
+			}
+			for _, t := range attacker.Pokemon.Types {
+				c.Matchups = append(c.Matchups, Matchup{
+					Attacker:   attacker.Pokemon.Name,
+					Defender:   defender.Pokemon.Name,
+					AttackType: t.Name,
+					Multiplier: d.Effectiveness(t.Name, defender.Pokemon.TypeNames()...),
+				})
+			}
+		}
+	}
+
+	bySpeed := append([]ComparedPokemon(nil), c.Pokemon...)
+	sort.SliceStable(bySpeed, func(i, j int) bool {
+		return bySpeed[i].Stats.Speed > bySpeed[j].Stats.Speed
+	})
+	for i, pokemon := range bySpeed {
+		c.SpeedOrder = append(c.SpeedOrder, pokemon.Name)
+		for _, slower := range bySpeed[i+1:] {
+			c.Outspeeds = append(c.Outspeeds, Outspeed{
+				Faster: pokemon.Name,
+				Slower: slower.Name,
+				Tie:    pokemon.Stats.Speed == slower.Stats.Speed,
+			})
+		}
+	}
+	return c
+}
+
+// multiplierAgainst looks up how much damage an attacking type deals to a
+// species. Types missing from its multipliers are neutral.
+func multiplierAgainst(mult *Mult, attackType string) float64 {
+	for _, mt := range mult.MonsterTypes {
+		if strings.EqualFold(mt.Type, attackType) {
+			return mt.Value()
+		}
+	}
+	return 1
+}
@@ -0,0 +1,140 @@
+package pokedata
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testDataset indexes the given species the way Load does.
+func testDataset(pokemons ...Pokemon) *Dataset {
+	d := &Dataset{
+		Pokemons:     pokemons,
+		Types:        make(map[int]Mult),
+		MonsterMoves: make(map[int]MonsterMoves),
+		Moves:        make(map[int]Move),
+		byID:         make(map[int]int),
+		byName:       make(map[string]int),
+	}
+	for i, pokemon := range pokemons {
+		d.byID[pokemon.NationalID] = i
+		d.byName[strings.ToLower(pokemon.Name)] = i
+	}
+	return d
+}
+
+func types(names ...string) []ListMapObject {
+	var list []ListMapObject
+	for _, name := range names {
+		list = append(list, ListMapObject{Name: name})
+	}
+	return list
+}
+
+func learns(ids ...int) MonsterMoves {
+	var moves MonsterMoves
+	for _, id := range ids {
+		moves.Moves = append(moves.Moves, LearnedMove{LearnType: "level up", ID: id})
+	}
+	return moves
+}
+
+func starters() *Dataset {
+	d := testDataset(
+		Pokemon{NationalID: 1, Name: "Bulbasaur", Types: types("grass", "poison"), HP: 45, Attack: 49, Defense: 49, SpAtk: 65, SpDef: 65, Speed: 45},
+		Pokemon{NationalID: 4, Name: "Charmander", Types: types("fire"), HP: 39, Attack: 52, Defense: 43, SpAtk: 60, SpDef: 50, Speed: 65},
+		// Faster than the real Squirtle, to tie with Charmander.
+		Pokemon{NationalID: 7, Name: "Squirtle", Types: types("water"), HP: 44, Attack: 48, Defense: 65, SpAtk: 50, SpDef: 64, Speed: 65},
+	)
+	for id, name := range map[int]string{1: "Tackle", 2: "Growl", 3: "Vine Whip", 4: "Ember", 5: "Water Gun"} {
+		d.Moves[id] = Move{ID: id, Name: name}
+	}
+	// Move 99 is missing from moves.json and doesn't count.
+	d.MonsterMoves[1] = learns(1, 2, 3, 99)
+	d.MonsterMoves[4] = learns(1, 2, 4, 99)
+	d.MonsterMoves[7] = learns(1, 5)
+
+	d.Chart = StandardTypeChart()
+	return d
+}
+
+func TestCompare(t *testing.T) {
+	c := starters().Compare(1, 4, 7)
+
+	if len(c.Pokemon) != 3 {
+		t.Fatalf("compared %d Pokémon, want 3", len(c.Pokemon))
+	}
+	bulbasaur, charmander := c.Pokemon[0], c.Pokemon[1]
+	if want := (BaseStats{45, 49, 49, 65, 65, 45, 318}); bulbasaur.Stats != want {
+		t.Errorf("Bulbasaur stats = %+v, want %+v", bulbasaur.Stats, want)
+	}
+	if bulbasaur.Deltas != (BaseStats{}) {
+		t.Errorf("Bulbasaur deltas = %+v, want zero", bulbasaur.Deltas)
+	}
+	if want := (BaseStats{-6, 3, -6, -5, -15, 20, -9}); charmander.Deltas != want {
+		t.Errorf("Charmander deltas = %+v, want %+v", charmander.Deltas, want)
+	}
+	if want := []string{"grass", "poison"}; !reflect.DeepEqual(bulbasaur.Types, want) {
+		t.Errorf("Bulbasaur types = %v, want %v", bulbasaur.Types, want)
+	}
+
+	if want := []string{"Tackle"}; !reflect.DeepEqual(c.SharedMoves, want) {
+		t.Errorf("shared moves = %v, want %v", c.SharedMoves, want)
+	}
+	// Growl is learned by two of the three, so it is neither shared nor
+	// exclusive.
+	for i, want := range [][]string{{"Vine Whip"}, {"Ember"}, {"Water Gun"}} {
+		if got := c.Pokemon[i].ExclusiveMoves; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s exclusive moves = %v, want %v", c.Pokemon[i].Name, got, want)
+		}
+	}
+
+	if want := []string{"Charmander", "Squirtle", "Bulbasaur"}; !reflect.DeepEqual(c.SpeedOrder, want) {
+		t.Errorf("speed order = %v, want %v", c.SpeedOrder, want)
+	}
+	wantOutspeeds := []Outspeed{
+		{Faster: "Charmander", Slower: "Squirtle", Tie: true},
+		{Faster: "Charmander", Slower: "Bulbasaur"},
+		{Faster: "Squirtle", Slower: "Bulbasaur"},
+	}
+	if !reflect.DeepEqual(c.Outspeeds, wantOutspeeds) {
+		t.Errorf("outspeeds = %+v, want %+v", c.Outspeeds, wantOutspeeds)
+	}
+}
+
+func TestCompareMatchups(t *testing.T) {
+	c := starters().Compare(1, 4, 7)
+
+	got := make(map[string]float64)
+	for _, m := range c.Matchups {
+		got[m.Attacker+" "+m.AttackType+" vs "+m.Defender] = m.Multiplier
+	}
+	want := map[string]float64{
+		"Bulbasaur grass vs Charmander":  0.5,
+		"Bulbasaur poison vs Charmander": 1,
+		"Bulbasaur grass vs Squirtle":    2,
+		"Bulbasaur poison vs Squirtle":   1,
+		"Charmander fire vs Bulbasaur":   2,
+		"Charmander fire vs Squirtle":    0.5,
+		"Squirtle water vs Bulbasaur":    0.5,
+		"Squirtle water vs Charmander":   2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchups = %v, want %v", got, want)
+	}
+}
+
+func TestCompareSkipsUnknown(t *testing.T) {
+	c := starters().Compare(4, 151)
+
+	if len(c.Pokemon) != 1 || c.Pokemon[0].Name != "Charmander" {
+		t.Fatalf("compared %+v, want only Charmander", c.Pokemon)
+	}
+	// On its own every move is shared and none is exclusive.
+	if want := []string{"Ember", "Growl", "Tackle"}; !reflect.DeepEqual(c.SharedMoves, want) {
+		t.Errorf("shared moves = %v, want %v", c.SharedMoves, want)
+	}
+	if len(c.Pokemon[0].ExclusiveMoves) != 0 || len(c.Matchups) != 0 || len(c.Outspeeds) != 0 {
+		t.Errorf("lone comparison = %+v, want no exclusives, matchups or outspeeds", c)
+	}
+}
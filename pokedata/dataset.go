@@ -0,0 +1,267 @@
+package pokedata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Dataset is every data file loaded once and indexed by national ID. Its
+// maps are keyed by national ID, except Moves which is keyed by move ID and
+// Descriptions which is keyed by description ID.
+type Dataset struct {
+	Pokemons     []Pokemon
+	Additional   map[int]AdditionalInfo
+	Descriptions map[int]Description
+	Evolutions   map[int]Evolution
+	Types        map[int]Mult
+	MonsterMoves map[int]MonsterMoves
+	Moves        map[int]Move
+	Experience   map[int]Experience
+
+	// Chart is the standard type chart. MonsterType.json is only checked
+	// against it: the crawler has at times scraped the same multipliers for
+	// every species, so it can't be trusted to describe the types.
+	Chart TypeChart
+
+	byID   map[int]int
+	byName map[string]int
+}
+
+// Load reads all the data files in dir, as written by the crawler.
+func Load(dir string) (*Dataset, error) {
+	d := &Dataset{
+		Additional:   make(map[int]AdditionalInfo),
+		Descriptions: make(map[int]Description),
+		Evolutions:   make(map[int]Evolution),
+		Types:        make(map[int]Mult),
+		MonsterMoves: make(map[int]MonsterMoves),
+		Moves:        make(map[int]Move),
+		Experience:   make(map[int]Experience),
+	}
+
+	if err := readJSON(filepath.Join(dir, "baseInfo.json"), &d.Pokemons); err != nil {
+		return nil, err
+	}
+
+	var infos []AdditionalInfo
+	if err := readJSON(filepath.Join(dir, "stats.json"), &infos); err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		d.Additional[atoi(info.ID)] = info
+	}
+
+	var descriptions []Description
+	if err := readJSON(filepath.Join(dir, "MonsterDescription.json"), &descriptions); err != nil {
+		return nil, err
+	}
+	for _, desc := range descriptions {
+		d.Descriptions[atoi(desc.ID)] = desc
+	}
+
+	var evolutions []Evolution
+	if err := readJSON(filepath.Join(dir, "evolution.json"), &evolutions); err != nil {
+		return nil, err
+	}
+	for _, evo := range evolutions {
+		d.Evolutions[atoi(evo.ID)] = evo
+	}
+
+	var types []Mult
+	if err := readJSON(filepath.Join(dir, "MonsterType.json"), &types); err != nil {
+		return nil, err
+	}
+	for _, mult := range types {
+		d.Types[mult.ID] = mult
+	}
+
+	var monsterMoves []MonsterMoves
+	if err := readJSON(filepath.Join(dir, "monsterMoves.json"), &monsterMoves); err != nil {
+		return nil, err
+	}
+	for _, moves := range monsterMoves {
+		d.MonsterMoves[atoi(moves.ID)] = moves
+	}
+
+	var moves []MoveRecord
+	if err := readJSON(filepath.Join(dir, "moves.json"), &moves); err != nil {
+		return nil, err
+	}
+	for _, record := range moves {
+		move := newMove(record)
+		d.Moves[move.ID] = move
+	}
+
+	var exps []Experience
+	if err := readJSON(filepath.Join(dir, "exp.json"), &exps); err != nil {
+		return nil, err
+	}
+	for _, exp := range exps {
+		d.Experience[atoi(exp.ID)] = exp
+	}
+
+	d.byID = make(map[int]int, len(d.Pokemons))
+	d.byName = make(map[string]int, len(d.Pokemons))
+	for i, pokemon := range d.Pokemons {
+		d.byID[pokemon.NationalID] = i
+		d.byName[strings.ToLower(pokemon.Name)] = i
+	}
+	d.Chart = StandardTypeChart()
+	return d, nil
+}
+
+// Pokemon returns the species with the given national ID.
+func (d *Dataset) Pokemon(nationalID int) (*Pokemon, bool) {
+	i, ok := d.byID[nationalID]
+	if !ok {
+		return nil, false
+	}
+	return &d.Pokemons[i], true
+}
+
+// PokemonByName returns the species with the given name, ignoring case.
+func (d *Dataset) PokemonByName(name string) (*Pokemon, bool) {
+	i, ok := d.byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, false
+	}
+	return &d.Pokemons[i], true
+}
+
+// Find returns the species named by key, which is either a national ID or a
+// name.
+func (d *Dataset) Find(key string) (*Pokemon, bool) {
+	if id, err := strconv.Atoi(strings.TrimSpace(key)); err == nil {
+		return d.Pokemon(id)
+	}
+	return d.PokemonByName(key)
+}
+
+// Info gathers everything known about the species with the given national ID,
+// with the details of each learnable move filled in.
+func (d *Dataset) Info(nationalID int) (PokemonInfo, bool) {
+	pokemon, ok := d.Pokemon(nationalID)
+	if !ok {
+		return PokemonInfo{}, false
+	}
+
+	info := d.Additional[nationalID]
+	evolution := d.Evolutions[nationalID]
+	mult := d.Types[nationalID]
+	exp := d.Experience[nationalID]
+	monsterMoves := d.MonsterMoves[nationalID]
+	monsterMoves.Moves = append([]LearnedMove(nil), monsterMoves.Moves...)
+	for i, move := range monsterMoves.Moves {
+		if details, exists := d.Moves[move.ID]; exists {
+			monsterMoves.Moves[i].Details = &details
+		}
+	}
+
+	return PokemonInfo{
+		Pokemon:      pokemon,
+		Additional:   &info,
+		Experience:   &exp,
+		Descriptions: d.SpeciesDescriptions(nationalID),
+		Evolution:    &evolution,
+		TypeInfo:     &mult,
+		MonsterMoves: &monsterMoves,
+	}, true
+}
+
+// SpeciesDescriptions returns the flavor texts of a species, one per game
+// version, in the order baseInfo.json links them. pokedex.org also links
+// some species to the descriptions of other species, so links named for
+// another species are skipped. A species none of whose own descriptions were
+// crawled falls back to the ones it links for the rest of its evolution line,
+// with Species saying whose they are; those of unrelated species never stand
+// in for its own.
+func (d *Dataset) SpeciesDescriptions(nationalID int) []Description {
+	pokemon, ok := d.Pokemon(nationalID)
+	if !ok {
+		return nil
+	}
+	if descriptions := d.linkedDescriptions(pokemon, []EvolutionStep{{NationalID: nationalID, Name: pokemon.Name}}); len(descriptions) > 0 {
+		return descriptions
+	}
+	var family []EvolutionStep
+	for _, step := range d.EvolutionLine(nationalID) {
+		if step.NationalID != nationalID {
+			family = append(family, step)
+		}
+	}
+	return d.linkedDescriptions(pokemon, family)
+}
+
+// linkedDescriptions returns the descriptions pokemon links that are named for
+// one of the given species. Species is set on those not named for pokemon.
+func (d *Dataset) linkedDescriptions(pokemon *Pokemon, species []EvolutionStep) []Description {
+	var descriptions []Description
+	for _, ref := range pokemon.Descriptions {
+		name, version, _ := strings.Cut(ref.Name, "_")
+		owner := ""
+		for _, step := range species {
+			if simplifyName(name) == simplifyName(step.Name) {
+				owner = step.Name
+				break
+			}
+		}
+		if owner == "" {
+			continue
+		}
+		id, ok := ref.ResourceID()
+		if !ok {
+			continue
+		}
+		if desc, ok := d.Descriptions[id]; ok {
+			desc.Version = strings.ReplaceAll(version, "_", " ")
+			if owner != pokemon.Name {
+				desc.Species = owner
+			}
+			descriptions = append(descriptions, desc)
+		}
+	}
+	return descriptions
+}
+
+// All returns the info of every species, in the order of baseInfo.json.
+func (d *Dataset) All() []PokemonInfo {
+	infos := make([]PokemonInfo, 0, len(d.Pokemons))
+	for _, pokemon := range d.Pokemons {
+		info, _ := d.Info(pokemon.NationalID)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func readJSON(filename string, v interface{}) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", filename, err)
+	}
+	return nil
+}
+
+// simplifyName reduces a species name to lowercase letters and digits, so
+// that "Mr. Mime", "Mr-mime" and "mr-mime" are all "mrmime" and "Nidoran♀"
+// is "nidoranf".
+func simplifyName(name string) string {
+	name = strings.NewReplacer("♀", "f", "♂", "m").Replace(strings.ToLower(name))
+	return strings.Map(func(r rune) rune {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,84 @@
+package pokedata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func describedBy(refs ...string) []ListMapObject {
+	var list []ListMapObject
+	for i := 0; i+1 < len(refs); i += 2 {
+		list = append(list, ListMapObject{Name: refs[i], ResourceURI: refs[i+1]})
+	}
+	return list
+}
+
+func TestSpeciesDescriptions(t *testing.T) {
+	d := testDataset(
+		Pokemon{NationalID: 1, Name: "Bulbasaur", Descriptions: describedBy(
+			"bulbasaur_gen_5", "/api/v1/description/15/",
+			"bulbasaur_gen_1_red", "/api/v1/description/3/",
+			// pokedex.org links Bulbasaur to Ivysaur's description too.
+			"ivysaur_gen_5", "/api/v1/description/29/",
+			"bulbasaur_gen_6_x", "/api/v1/description/99/",
+			"bulbasaur_gen_6_y", "/api/v1/description/",
+		)},
+		Pokemon{NationalID: 122, Name: "Mr. Mime", Descriptions: describedBy(
+			"mr-mime_gen_5", "/api/v1/description/1830/",
+		)},
+		Pokemon{NationalID: 29, Name: "Nidoran♀", Descriptions: describedBy(
+			"nidoran-f_gen_5", "/api/v1/description/435/",
+		)},
+		// Pidgeot's and Mew's own descriptions weren't crawled. Pidgeotto
+		// is in Pidgeot's evolution line; Mewtwo isn't in Mew's.
+		Pokemon{NationalID: 17, Name: "Pidgeotto", Descriptions: describedBy(
+			"pidgeotto_gen_5", "/api/v1/description/269/",
+		)},
+		Pokemon{NationalID: 18, Name: "Pidgeot", Descriptions: describedBy(
+			"pidgeotto_gen_5", "/api/v1/description/269/",
+			"pidgeot_gen_5", "/api/v1/description/285/",
+		)},
+		Pokemon{NationalID: 151, Name: "Mew", Descriptions: describedBy(
+			"mewtwo_gen_5", "/api/v1/description/2459/",
+			"mew_gen_5", "/api/v1/description/2475/",
+		)},
+	)
+	d.Evolutions = map[int]Evolution{
+		17: {To: []EvolutionDetail{{NationalID: 18, Name: "Pidgeot", Method: "level_up", Level: 36}}},
+		18: {From: []EvolutionDetail{{NationalID: 17, Name: "Pidgeotto", Method: "level_up", Level: 36}}},
+	}
+	d.Descriptions = map[int]Description{
+		3:    {Description: "A strange seed was planted on its back at birth.", ID: "0000003"},
+		15:   {Description: "It carries a seed on its back right from birth.", ID: "0000015"},
+		29:   {Description: "When the bulb on its back grows large, it can't stand.", ID: "0000029"},
+		435:  {Description: "Its horn secretes a powerful poison.", ID: "0000435"},
+		269:  {Description: "It flies over its wide territory.", ID: "0000269"},
+		1830: {Description: "It is a master of pantomime.", ID: "0001830"},
+		2459: {Description: "It was created by a scientist.", ID: "0002459"},
+	}
+
+	tests := []struct {
+		nationalID int
+		want       []Description
+	}{
+		// Descriptions are joined on the number in the link, not the
+		// position, and dangling or malformed links are skipped.
+		{1, []Description{
+			{Description: "It carries a seed on its back right from birth.", Version: "gen 5", ID: "0000015"},
+			{Description: "A strange seed was planted on its back at birth.", Version: "gen 1 red", ID: "0000003"},
+		}},
+		{122, []Description{{Description: "It is a master of pantomime.", Version: "gen 5", ID: "0001830"}}},
+		{29, []Description{{Description: "Its horn secretes a powerful poison.", Version: "gen 5", ID: "0000435"}}},
+		{17, []Description{{Description: "It flies over its wide territory.", Version: "gen 5", ID: "0000269"}}},
+		// Pidgeot falls back to Pidgeotto's description, but Mew doesn't
+		// take Mewtwo's.
+		{18, []Description{{Description: "It flies over its wide territory.", Version: "gen 5", Species: "Pidgeotto", ID: "0000269"}}},
+		{151, nil},
+		{150, nil},
+	}
+	for _, tt := range tests {
+		if got := d.SpeciesDescriptions(tt.nationalID); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SpeciesDescriptions(%d) = %+v, want %+v", tt.nationalID, got, tt.want)
+		}
+	}
+}
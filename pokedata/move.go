@@ -0,0 +1,319 @@
+package pokedata
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DamageClass says which stats a move's damage is calculated from, or that it
+// deals no damage.
+type DamageClass string
+
+const (
+	DamageClassPhysical DamageClass = "physical"
+	DamageClassSpecial  DamageClass = "special"
+	DamageClassStatus   DamageClass = "status"
+)
+
+// Move is a move from moves.json. Power, PP and accuracy are nil for moves
+// that don't have one.
+type Move struct {
+	ID          int         `json:"id"`
+	Identifier  string      `json:"identifier"`
+	Name        string      `json:"name"`
+	TypeName    string      `json:"type_name"`
+	Power       *int        `json:"power"`
+	PP          *int        `json:"pp"`
+	Accuracy    *int        `json:"accuracy"`
+	DamageClass DamageClass `json:"damage_class"`
+	// Priority is the bracket the move acts in, before Speed is compared.
+	Priority    int         `json:"priority"`
+	Effects     MoveEffects `json:"effects"`
+	Description string      `json:"description"`
+}
+
+// MoveEffects are the secondary effects read from a move's description.
+type MoveEffects struct {
+	StatChanges []StatChange  `json:"stat_changes,omitempty"`
+	Status      *StatusEffect `json:"status,omitempty"`
+	Recoil      *Recoil       `json:"recoil,omitempty"`
+	// MinHits and MaxHits are set for moves that hit more than once a turn.
+	MinHits int `json:"min_hits,omitempty"`
+	MaxHits int `json:"max_hits,omitempty"`
+}
+
+// StatChange raises or lowers the stat stages of the user or the target.
+// Stats are "attack", "defense", "sp_atk", "sp_def", "speed", "accuracy" and
+// "evasion"; Chance is a percentage.
+type StatChange struct {
+	Target string   `json:"target"`
+	Stats  []string `json:"stats"`
+	Stages int      `json:"stages"`
+	Chance int      `json:"chance"`
+}
+
+// StatusEffect is a major status a move can inflict on the target. When
+// several statuses are listed one of them is picked at random.
+type StatusEffect struct {
+	Statuses []string `json:"statuses"`
+	Chance   int      `json:"chance"`
+}
+
+// Recoil is the damage a move does to its user, as a fraction of the damage
+// dealt ("damage"), of the user's max HP ("max_hp") or of its current HP
+// ("current_hp").
+type Recoil struct {
+	Fraction float64 `json:"fraction"`
+	Of       string  `json:"of"`
+}
+
+// Damaging reports whether the move deals damage.
+func (m Move) Damaging() bool {
+	return m.DamageClass != DamageClassStatus
+}
+
+// MoveRecord is a move as moves.json stores it. Power, PP and accuracy are
+// numbers, or an empty string for moves that don't have one. Priority and
+// DamageClass are only recorded by sources that know them.
+type MoveRecord struct {
+	TypeName    string      `json:"type_name"`
+	Identifier  string      `json:"identifier"`
+	Power       interface{} `json:"power"`
+	PP          interface{} `json:"pp"`
+	Accuracy    interface{} `json:"accuracy"`
+	Description string      `json:"description"`
+	Name        string      `json:"name"`
+	Priority    *int        `json:"priority,omitempty"`
+	DamageClass DamageClass `json:"damage_class,omitempty"`
+	ID          string      `json:"_id"`
+	Rev         string      `json:"_rev,omitempty"`
+}
+
+func newMove(record MoveRecord) Move {
+	move := Move{
+		ID:          atoi(record.ID),
+		Identifier:  record.Identifier,
+		Name:        record.Name,
+		TypeName:    record.TypeName,
+		Power:       optionalInt(record.Power),
+		PP:          optionalInt(record.PP),
+		Accuracy:    optionalInt(record.Accuracy),
+		Priority:    movePriorities[record.Identifier],
+		DamageClass: record.DamageClass,
+		Effects:     parseMoveEffects(record.Description),
+		Description: record.Description,
+	}
+	if record.Priority != nil {
+		move.Priority = *record.Priority
+	}
+	// The damage class comes from the record when the source knows it. If it
+	// doesn't, moves whose power varies, such as Magnitude or Seismic Toss,
+	// have none in moves.json but still describe the damage they inflict.
+	if class, ok := moveDamageClasses[record.Identifier]; ok && move.DamageClass == "" {
+		move.DamageClass = class
+	}
+	switch {
+	case move.DamageClass != "":
+	case (move.Power == nil || *move.Power == 0) && !strings.HasPrefix(record.Description, "Inflicts"):
+		move.DamageClass = DamageClassStatus
+	case physicalTypes[move.TypeName]:
+		move.DamageClass = DamageClassPhysical
+	default:
+		move.DamageClass = DamageClassSpecial
+	}
+	return move
+}
+
+func optionalInt(value interface{}) *int {
+	var n int
+	switch v := value.(type) {
+	case float64:
+		n = int(v)
+	case string:
+		var err error
+		if n, err = strconv.Atoi(v); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return &n
+}
+
+// physicalTypes are the types whose damaging moves are physical. moves.json
+// doesn't record damage classes, so they follow the type, as they did before
+// the fourth generation.
+var physicalTypes = map[string]bool{
+	"normal":   true,
+	"fighting": true,
+	"flying":   true,
+	"poison":   true,
+	"ground":   true,
+	"rock":     true,
+	"bug":      true,
+	"ghost":    true,
+	"steel":    true,
+}
+
+// moveDamageClasses lists the moves whose damage class can't be told from
+// moves.json: damaging moves without power whose descriptions don't start
+// with "Inflicts", and a status move whose description says it does.
+var moveDamageClasses = map[string]DamageClass{
+	"bide":           DamageClassPhysical,
+	"counter":        DamageClassPhysical,
+	"metal-burst":    DamageClassPhysical,
+	"mirror-coat":    DamageClassSpecial,
+	"present":        DamageClassPhysical,
+	"baby-doll-eyes": DamageClassStatus,
+}
+
+// movePriorities lists the moves that don't act in the normal bracket, which
+// moves.json doesn't record either.
+var movePriorities = map[string]int{
+	"helping-hand":  5,
+	"detect":        4,
+	"endure":        4,
+	"magic-coat":    4,
+	"protect":       4,
+	"snatch":        4,
+	"fake-out":      3,
+	"follow-me":     3,
+	"quick-guard":   3,
+	"rage-powder":   3,
+	"wide-guard":    3,
+	"extreme-speed": 2,
+	"feint":         2,
+	"ally-switch":   1,
+	"aqua-jet":      1,
+	"bide":          1,
+	"bullet-punch":  1,
+	"ice-shard":     1,
+	"mach-punch":    1,
+	"quick-attack":  1,
+	"shadow-sneak":  1,
+	"sucker-punch":  1,
+	"vacuum-wave":   1,
+	"vital-throw":   -1,
+	"focus-punch":   -3,
+	"avalanche":     -4,
+	"revenge":       -4,
+	"counter":       -5,
+	"mirror-coat":   -5,
+	"circle-throw":  -6,
+	"dragon-tail":   -6,
+	"roar":          -6,
+	"whirlwind":     -6,
+	"magic-room":    -7,
+	"trick-room":    -7,
+	"wonder-room":   -7,
+}
+
+var (
+	statChangePattern   = regexp.MustCompile(`(?i)(?:has an? (\d+)% chance to )?(raise|lower)s? (?:all of )?(?:the )?(user|target)'s ([a-z ,]+?) (?:by )?(one|two|three) stages?`)
+	chanceStatusPattern = regexp.MustCompile(`Has a (\d+)% chance to (badly poison|burn, freeze, or paralyze|burn|poison|paralyze|freeze|put the target to sleep)`)
+	directStatusPattern = regexp.MustCompile(`^(Badly poisons|Burns|Poisons|Paralyzes|Freezes) the target\.|^Puts the target to sleep\.`)
+	recoilPattern       = regexp.MustCompile(`User takes (\d+)/(\d+) (?:of )?(the damage it inflicts|its max HP|its current HP) in recoil`)
+	hitRangePattern     = regexp.MustCompile(`Hits (\d+)–(\d+) times`)
+	hitCountPattern     = regexp.MustCompile(`Hits (twice|three times)`)
+	statListSeparator   = regexp.MustCompile(`,\s*(?:and\s+)?|\s+and\s+`)
+)
+
+var statusVerbs = map[string][]string{
+	"badly poison":              {"toxic"},
+	"burn, freeze, or paralyze": {"burn", "freeze", "paralysis"},
+	"burn":                      {"burn"},
+	"poison":                    {"poison"},
+	"paralyze":                  {"paralysis"},
+	"freeze":                    {"freeze"},
+	"put the target to sleep":   {"sleep"},
+	"Badly poisons":             {"toxic"},
+	"Burns":                     {"burn"},
+	"Poisons":                   {"poison"},
+	"Paralyzes":                 {"paralysis"},
+	"Freezes":                   {"freeze"},
+}
+
+var stageCounts = map[string]int{"one": 1, "two": 2, "three": 3}
+
+var moveStatNames = map[string]string{
+	"attack":          "attack",
+	"defense":         "defense",
+	"special attack":  "sp_atk",
+	"special defense": "sp_def",
+	"speed":           "speed",
+	"accuracy":        "accuracy",
+	"evasion":         "evasion",
+}
+
+// parseMoveEffects reads the effects of a move from sentences in its
+// description such as "Has a 10% chance to burn the target." or "User takes
+// 1/3 the damage it inflicts in recoil."
+func parseMoveEffects(description string) MoveEffects {
+	var effects MoveEffects
+
+	for _, match := range statChangePattern.FindAllStringSubmatch(description, -1) {
+		change := StatChange{Target: strings.ToLower(match[3]), Stages: stageCounts[match[5]], Chance: 100}
+		if match[1] != "" {
+			change.Chance, _ = strconv.Atoi(match[1])
+		}
+		if strings.EqualFold(match[2], "lower") {
+			change.Stages = -change.Stages
+		}
+		change.Stats = parseStatList(match[4])
+		if len(change.Stats) > 0 {
+			effects.StatChanges = append(effects.StatChanges, change)
+		}
+	}
+
+	if match := chanceStatusPattern.FindStringSubmatch(description); match != nil {
+		chance, _ := strconv.Atoi(match[1])
+		effects.Status = &StatusEffect{Statuses: statusVerbs[match[2]], Chance: chance}
+	} else if match := directStatusPattern.FindStringSubmatch(description); match != nil {
+		statuses := []string{"sleep"}
+		if match[1] != "" {
+			statuses = statusVerbs[match[1]]
+		}
+		effects.Status = &StatusEffect{Statuses: statuses, Chance: 100}
+	}
+
+	if match := recoilPattern.FindStringSubmatch(description); match != nil {
+		numerator, _ := strconv.ParseFloat(match[1], 64)
+		denominator, _ := strconv.ParseFloat(match[2], 64)
+		recoil := &Recoil{Fraction: numerator / denominator, Of: "damage"}
+		switch match[3] {
+		case "its max HP":
+			recoil.Of = "max_hp"
+		case "its current HP":
+			recoil.Of = "current_hp"
+		}
+		effects.Recoil = recoil
+	}
+
+	if match := hitRangePattern.FindStringSubmatch(description); match != nil {
+		effects.MinHits, _ = strconv.Atoi(match[1])
+		effects.MaxHits, _ = strconv.Atoi(match[2])
+	} else if match := hitCountPattern.FindStringSubmatch(description); match != nil {
+		effects.MinHits, effects.MaxHits = 2, 2
+		if match[1] == "three times" {
+			effects.MinHits, effects.MaxHits = 3, 3
+		}
+	}
+	return effects
+}
+
+// parseStatList turns a list such as "Attack, Defense, and accuracy" into
+// stat names. "stats" means every stat but HP, accuracy and evasion.
+func parseStatList(list string) []string {
+	list = strings.ToLower(strings.TrimSpace(list))
+	if list == "stats" {
+		return []string{"attack", "defense", "sp_atk", "sp_def", "speed"}
+	}
+	var stats []string
+	for _, part := range statListSeparator.Split(list, -1) {
+		if stat, ok := moveStatNames[strings.TrimSpace(part)]; ok {
+			stats = append(stats, stat)
+		}
+	}
+	return stats
+}
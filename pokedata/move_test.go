@@ -0,0 +1,223 @@
+package pokedata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMoveEffects(t *testing.T) {
+	tests := []struct {
+		move        string
+		description string
+		want        MoveEffects
+	}{
+		{
+			"tackle",
+			"Inflicts regular damage.",
+			MoveEffects{},
+		},
+		{
+			"thunder-punch",
+			"Inflicts regular damage.  Has a 10% chance to paralyze the target.",
+			MoveEffects{Status: &StatusEffect{Statuses: []string{"paralysis"}, Chance: 10}},
+		},
+		{
+			"poison-fang",
+			"Inflicts regular damage.  Has a 30% chance to badly poison the target.",
+			MoveEffects{Status: &StatusEffect{Statuses: []string{"toxic"}, Chance: 30}},
+		},
+		{
+			"tri-attack",
+			"Inflicts regular damage.  Has a 20% chance to burn, freeze, or paralyze the target.  One of these effects is selected at random; they do not each have independent chances to occur.",
+			MoveEffects{Status: &StatusEffect{Statuses: []string{"burn", "freeze", "paralysis"}, Chance: 20}},
+		},
+		{
+			"toxic",
+			"Badly poisons the target.  Never misses when used by a poison-type Pokémon.",
+			MoveEffects{Status: &StatusEffect{Statuses: []string{"toxic"}, Chance: 100}},
+		},
+		{
+			"will-o-wisp",
+			"Burns the target.",
+			MoveEffects{Status: &StatusEffect{Statuses: []string{"burn"}, Chance: 100}},
+		},
+		{
+			"hypnosis",
+			"Puts the target to sleep.",
+			MoveEffects{Status: &StatusEffect{Statuses: []string{"sleep"}, Chance: 100}},
+		},
+		{
+			"double-edge",
+			"Inflicts regular damage.  User takes 1/3 the damage it inflicts in recoil.",
+			MoveEffects{Recoil: &Recoil{Fraction: 1.0 / 3, Of: "damage"}},
+		},
+		{
+			"flare-blitz",
+			"Inflicts regular damage.  User takes 1/3 the damage it inflicts in recoil.  Has a 10% chance to burn the target.  Frozen Pokémon may use this move, in which case they will thaw.",
+			MoveEffects{
+				Status: &StatusEffect{Statuses: []string{"burn"}, Chance: 10},
+				Recoil: &Recoil{Fraction: 1.0 / 3, Of: "damage"},
+			},
+		},
+		{
+			"struggle",
+			"Inflicts typeless regular damage.  User takes 1/4 its max HP in recoil.  Ignores accuracy and evasion modifiers.",
+			MoveEffects{Recoil: &Recoil{Fraction: 0.25, Of: "max_hp"}},
+		},
+		{
+			"fury-attack",
+			"Inflicts regular damage.  Hits 2–5 times in one turn.\n\nHas a 3/8 chance each to hit 2 or 3 times, and a 1/8 chance each to hit 4 or 5 times.  Averages to 3 hits per use.",
+			MoveEffects{MinHits: 2, MaxHits: 5},
+		},
+		{
+			"double-kick",
+			"Inflicts regular damage.  Hits twice in one turn.",
+			MoveEffects{MinHits: 2, MaxHits: 2},
+		},
+		{
+			"triple-kick",
+			"Inflicts regular damage.  Hits three times in the same turn.  The second hit has double power, and the third hit has triple power.",
+			MoveEffects{MinHits: 3, MaxHits: 3},
+		},
+		{
+			"growl",
+			"Lowers the target's Attack by one stage.",
+			MoveEffects{StatChanges: []StatChange{{Target: "target", Stats: []string{"attack"}, Stages: -1, Chance: 100}}},
+		},
+		{
+			"swords-dance",
+			"Raises the user's Attack by two stages.",
+			MoveEffects{StatChanges: []StatChange{{Target: "user", Stats: []string{"attack"}, Stages: 2, Chance: 100}}},
+		},
+		{
+			"charge-beam",
+			"Inflicts regular damage.  Has a 70% chance to raise the user's Special Attack by one stage.",
+			MoveEffects{StatChanges: []StatChange{{Target: "user", Stats: []string{"sp_atk"}, Stages: 1, Chance: 70}}},
+		},
+		{
+			"close-combat",
+			"Inflicts regular damage, then lowers the user's Defense and Special Defense by one stage each.",
+			MoveEffects{StatChanges: []StatChange{{Target: "user", Stats: []string{"defense", "sp_def"}, Stages: -1, Chance: 100}}},
+		},
+		{
+			"ancient-power",
+			"Inflicts regular damage. Has a 10% chance to raise all of the user's stats one stage.",
+			MoveEffects{StatChanges: []StatChange{{Target: "user", Stats: []string{"attack", "defense", "sp_atk", "sp_def", "speed"}, Stages: 1, Chance: 10}}},
+		},
+		{
+			"shell-smash",
+			"Raises the user's Attack, Special Attack, and Speed by two stages each.  Lowers the user's Defense and Special Defense by one stage each.",
+			MoveEffects{StatChanges: []StatChange{
+				{Target: "user", Stats: []string{"attack", "sp_atk", "speed"}, Stages: 2, Chance: 100},
+				{Target: "user", Stats: []string{"defense", "sp_def"}, Stages: -1, Chance: 100},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		if got := parseMoveEffects(tt.description); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseMoveEffects = %+v, want %+v", tt.move, got, tt.want)
+		}
+	}
+}
+
+func TestNewMove(t *testing.T) {
+	tests := []struct {
+		record   MoveRecord
+		power    *int
+		accuracy *int
+		class    DamageClass
+		priority int
+	}{
+		{
+			MoveRecord{ID: "00033", Identifier: "tackle", TypeName: "normal", Power: 50.0, PP: 35.0, Accuracy: 100.0, Description: "Inflicts regular damage."},
+			intPtr(50), intPtr(100), DamageClassPhysical, 0,
+		},
+		{
+			MoveRecord{ID: "00007", Identifier: "fire-punch", TypeName: "fire", Power: 75.0, PP: 15.0, Accuracy: 100.0, Description: "Inflicts regular damage.  Has a 10% chance to burn the target."},
+			intPtr(75), intPtr(100), DamageClassSpecial, 0,
+		},
+		{
+			MoveRecord{ID: "00098", Identifier: "quick-attack", TypeName: "normal", Power: 40.0, PP: 30.0, Accuracy: 100.0, Description: "Inflicts regular damage."},
+			intPtr(40), intPtr(100), DamageClassPhysical, 1,
+		},
+		{
+			MoveRecord{ID: "00045", Identifier: "growl", TypeName: "normal", Power: "", PP: 40.0, Accuracy: 100.0, Description: "Lowers the target's Attack by one stage."},
+			nil, intPtr(100), DamageClassStatus, 0,
+		},
+		{
+			MoveRecord{ID: "00014", Identifier: "swords-dance", TypeName: "normal", Power: "", PP: 30.0, Accuracy: "", Description: "Raises the user's Attack by two stages."},
+			nil, nil, DamageClassStatus, 0,
+		},
+		// Seismic Toss has no power but still deals damage.
+		{
+			MoveRecord{ID: "00069", Identifier: "seismic-toss", TypeName: "fighting", Power: "", PP: 20.0, Accuracy: 100.0, Description: "Inflicts damage equal to the user's level."},
+			nil, intPtr(100), DamageClassPhysical, 0,
+		},
+		{
+			MoveRecord{ID: "00046", Identifier: "roar", TypeName: "normal", Power: "", PP: 20.0, Accuracy: 100.0, Description: "Switches the target out for another of its trainer's Pokémon selected at random."},
+			nil, intPtr(100), DamageClassStatus, -6,
+		},
+		// Counter, Bide and Mirror Coat have no power and don't start with
+		// "Inflicts", yet they deal damage.
+		{
+			MoveRecord{ID: "00068", Identifier: "counter", TypeName: "fighting", Power: "", PP: 20.0, Accuracy: 100.0, Description: "Targets the last opposing Pokémon to hit the user with a physical move this turn.  Inflicts twice the damage that move did to the user."},
+			nil, intPtr(100), DamageClassPhysical, -5,
+		},
+		{
+			MoveRecord{ID: "00117", Identifier: "bide", TypeName: "normal", Power: "", PP: 10.0, Accuracy: "", Description: "User waits for two turns.  On the second turn, the user inflicts twice the damage it accumulated on the last Pokémon to hit it."},
+			nil, nil, DamageClassPhysical, 1,
+		},
+		{
+			MoveRecord{ID: "00243", Identifier: "mirror-coat", TypeName: "psychic", Power: "", PP: 20.0, Accuracy: 100.0, Description: "Targets the last opposing Pokémon to hit the user with a special move this turn.  Inflicts twice the damage that move did to the user."},
+			nil, intPtr(100), DamageClassSpecial, -5,
+		},
+		// Baby-Doll Eyes only lowers Attack, whatever its description says.
+		{
+			MoveRecord{ID: "00608", Identifier: "baby-doll-eyes", TypeName: "fairy", Power: "", PP: 30.0, Accuracy: 100.0, Description: "Inflicts regular damage."},
+			nil, intPtr(100), DamageClassStatus, 0,
+		},
+		// PokéAPI records the damage class and priority, which win over the
+		// guesses.
+		{
+			MoveRecord{ID: "00127", Identifier: "waterfall", TypeName: "water", Power: 80.0, PP: 15.0, Accuracy: 100.0, DamageClass: DamageClassPhysical, Priority: intPtr(0), Description: "Inflicts regular damage."},
+			intPtr(80), intPtr(100), DamageClassPhysical, 0,
+		},
+		{
+			MoveRecord{ID: "00068", Identifier: "counter", TypeName: "fighting", Power: "", PP: 20.0, Accuracy: 100.0, DamageClass: DamageClassPhysical, Priority: intPtr(-5), Description: "Inflicts twice the damage that move did to the user."},
+			nil, intPtr(100), DamageClassPhysical, -5,
+		},
+		{
+			MoveRecord{ID: "00117", Identifier: "bide", TypeName: "normal", Power: "", PP: 10.0, Accuracy: "", DamageClass: DamageClassPhysical, Priority: intPtr(0), Description: "User waits for two turns."},
+			nil, nil, DamageClassPhysical, 0,
+		},
+	}
+	for _, tt := range tests {
+		move := newMove(tt.record)
+		if move.ID != atoi(tt.record.ID) || move.Identifier != tt.record.Identifier {
+			t.Errorf("%s: ID = %d, identifier = %q", tt.record.Identifier, move.ID, move.Identifier)
+		}
+		if !reflect.DeepEqual(move.Power, tt.power) || !reflect.DeepEqual(move.Accuracy, tt.accuracy) {
+			t.Errorf("%s: power = %v, accuracy = %v, want %v, %v", tt.record.Identifier, deref(move.Power), deref(move.Accuracy), deref(tt.power), deref(tt.accuracy))
+		}
+		if move.DamageClass != tt.class {
+			t.Errorf("%s: damage class = %s, want %s", tt.record.Identifier, move.DamageClass, tt.class)
+		}
+		if move.Damaging() != (tt.class != DamageClassStatus) {
+			t.Errorf("%s: Damaging = %v", tt.record.Identifier, move.Damaging())
+		}
+		if move.Priority != tt.priority {
+			t.Errorf("%s: priority = %d, want %d", tt.record.Identifier, move.Priority, tt.priority)
+		}
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func deref(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
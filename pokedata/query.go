@@ -0,0 +1,368 @@
+package pokedata
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StatFilter keeps species whose stat compares to Value with Op, one of
+// "<", "<=", "=", ">=" and ">". Stat is hp, attack, defense, sp_atk, sp_def,
+// speed or total.
+type StatFilter struct {
+	Stat  string
+	Op    string
+	Value int
+}
+
+// Query selects, sorts and pages species. Empty fields don't filter.
+type Query struct {
+	// Types must all be types of the species.
+	Types    []string
+	Ability  string
+	EggGroup string
+	Stats    []StatFilter
+	// Move is a move the species can learn, by name or identifier.
+	Move string
+	// Stage is the evolution stage, 1 for species that don't evolve from
+	// anything. FullyEvolved keeps species that don't evolve any further.
+	Stage        int
+	FullyEvolved bool
+
+	// SortBy is id, name or a stat. The default is national ID order.
+	SortBy     string
+	Descending bool
+	Offset     int
+	Limit      int
+}
+
+// SearchResult is one page of matching species and how many matched in all.
+type SearchResult struct {
+	Total    int
+	Pokemons []Pokemon
+}
+
+var statNames = map[string]bool{
+	"hp": true, "attack": true, "defense": true, "sp_atk": true, "sp_def": true, "speed": true, "total": true,
+}
+
+// Stat returns a base stat of the species by name, or the sum of all of them
+// for "total".
+func (p *Pokemon) Stat(name string) (int, bool) {
+	switch name {
+	case "hp":
+		return p.HP, true
+	case "attack":
+		return p.Attack, true
+	case "defense":
+		return p.Defense, true
+	case "sp_atk":
+		return p.SpAtk, true
+	case "sp_def":
+		return p.SpDef, true
+	case "speed":
+		return p.Speed, true
+	case "total":
+		return p.HP + p.Attack + p.Defense + p.SpAtk + p.SpDef + p.Speed, true
+	}
+	return 0, false
+}
+
+// EggGroupList splits the egg groups of stats.json, which the crawler saved
+// as a single string such as "]Monster, Grass".
+func (a AdditionalInfo) EggGroupList() []string {
+	var groups []string
+	for _, group := range strings.Split(strings.TrimLeft(a.EggGroups, "]"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// ParseQuery reads a query written as space-separated terms, for use from the
+// command line:
+//
+//	type:fire type:flying ability:blaze egg:dragon move:flamethrower
+//	speed>100 total>=500 stage:2 stage:final sort:-speed limit:10 page:2
+//
+// A leading "-" on the sort key sorts in descending order.
+func ParseQuery(input string) (Query, error) {
+	var q Query
+	page := 0
+	for _, term := range strings.Fields(input) {
+		if key, value, ok := strings.Cut(term, ":"); ok {
+			var err error
+			switch strings.ToLower(key) {
+			case "type":
+				q.Types = append(q.Types, value)
+			case "ability":
+				q.Ability = value
+			case "egg":
+				q.EggGroup = value
+			case "move":
+				q.Move = value
+			case "stage":
+				if value == "final" {
+					q.FullyEvolved = true
+				} else {
+					q.Stage, err = strconv.Atoi(value)
+				}
+			case "sort":
+				q.SortBy = strings.TrimPrefix(value, "-")
+				q.Descending = strings.HasPrefix(value, "-")
+			case "limit":
+				q.Limit, err = atoiNonNegative(value)
+			case "offset":
+				q.Offset, err = atoiNonNegative(value)
+			case "page":
+				page, err = atoiNonNegative(value)
+			default:
+				return Query{}, fmt.Errorf("unknown search term %q", term)
+			}
+			if err != nil {
+				return Query{}, fmt.Errorf("bad value in %q: %w", term, err)
+			}
+			continue
+		}
+
+		filter, err := parseStatFilter(term)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Stats = append(q.Stats, filter)
+	}
+
+	if page > 0 {
+		if q.Limit <= 0 {
+			return Query{}, fmt.Errorf("page needs a limit")
+		}
+		if page-1 > math.MaxInt/q.Limit {
+			return Query{}, fmt.Errorf("page %d is too far", page)
+		}
+		q.Offset = (page - 1) * q.Limit
+	}
+	if q.SortBy != "" && q.SortBy != "id" && q.SortBy != "name" && !statNames[q.SortBy] {
+		return Query{}, fmt.Errorf("can't sort by %q", q.SortBy)
+	}
+	return q, nil
+}
+
+func atoiNonNegative(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err == nil && n < 0 {
+		return 0, fmt.Errorf("%d is negative", n)
+	}
+	return n, err
+}
+
+func parseStatFilter(term string) (StatFilter, error) {
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		stat, value, ok := strings.Cut(term, op)
+		if !ok {
+			continue
+		}
+		stat = strings.ToLower(stat)
+		if !statNames[stat] {
+			return StatFilter{}, fmt.Errorf("unknown stat %q", stat)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return StatFilter{}, fmt.Errorf("bad value in %q: %w", term, err)
+		}
+		return StatFilter{Stat: stat, Op: op, Value: n}, nil
+	}
+	return StatFilter{}, fmt.Errorf("unknown search term %q", term)
+}
+
+func (f StatFilter) match(pokemon *Pokemon) bool {
+	stat, ok := pokemon.Stat(f.Stat)
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case "<":
+		return stat < f.Value
+	case "<=":
+		return stat <= f.Value
+	case "=":
+		return stat == f.Value
+	case ">=":
+		return stat >= f.Value
+	case ">":
+		return stat > f.Value
+	}
+	return false
+}
+
+// Search returns the species matching the query, sorted and paged.
+func (d *Dataset) Search(q Query) SearchResult {
+	var matches []Pokemon
+	for i := range d.Pokemons {
+		if d.matches(&d.Pokemons[i], q) {
+			matches = append(matches, d.Pokemons[i])
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := &matches[i], &matches[j]
+		if q.Descending {
+			a, b = b, a
+		}
+		switch q.SortBy {
+		case "", "id":
+			return a.NationalID < b.NationalID
+		case "name":
+			return a.Name < b.Name
+		}
+		sa, _ := a.Stat(q.SortBy)
+		sb, _ := b.Stat(q.SortBy)
+		return sa < sb
+	})
+
+	result := SearchResult{Total: len(matches)}
+	start := q.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := len(matches)
+	if q.Limit > 0 && q.Limit < end-start {
+		end = start + q.Limit
+	}
+	result.Pokemons = matches[start:end]
+	return result
+}
+
+func (d *Dataset) matches(pokemon *Pokemon, q Query) bool {
+	for _, typeName := range q.Types {
+		if !hasName(pokemon.Types, typeName) {
+			return false
+		}
+	}
+	if q.Ability != "" && !hasName(pokemon.Abilities, q.Ability) {
+		return false
+	}
+	if q.EggGroup != "" && !containsFold(d.Additional[pokemon.NationalID].EggGroupList(), q.EggGroup) {
+		return false
+	}
+	for _, filter := range q.Stats {
+		if !filter.match(pokemon) {
+			return false
+		}
+	}
+	if q.Move != "" && !d.CanLearn(pokemon.NationalID, q.Move) {
+		return false
+	}
+	if q.Stage > 0 && d.EvolutionStage(pokemon.NationalID) != q.Stage {
+		return false
+	}
+	if q.FullyEvolved && len(d.Evolutions[pokemon.NationalID].To) > 0 {
+		return false
+	}
+	return true
+}
+
+// CanLearn reports whether the species learns a move, by any method. The move
+// is matched by name or identifier, so "Fire Punch" and "fire-punch" both work.
+func (d *Dataset) CanLearn(nationalID int, move string) bool {
+	for _, learned := range d.MonsterMoves[nationalID].Moves {
+		if details, ok := d.Moves[learned.ID]; ok && moveNameMatches(details, move) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvolutionStage is 1 for species that don't evolve from anything, 2 for
+// their evolutions and so on.
+func (d *Dataset) EvolutionStage(nationalID int) int {
+	stage := 1
+	for seen := map[int]bool{nationalID: true}; ; stage++ {
+		from := d.Evolutions[nationalID].From
+		if len(from) == 0 || seen[from[0].NationalID] {
+			return stage
+		}
+		nationalID = from[0].NationalID
+		seen[nationalID] = true
+	}
+}
+
+func moveNameMatches(move Move, name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.ToLower(move.Name) == name || move.Identifier == strings.ReplaceAll(name, " ", "-")
+}
+
+func hasName(objects []ListMapObject, name string) bool {
+	for _, object := range objects {
+		if strings.EqualFold(object.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvolutionStep is one species in an evolution line and how it is reached
+// from the species before it.
+type EvolutionStep struct {
+	Stage      int    `json:"stage"`
+	NationalID int    `json:"national_id"`
+	Name       string `json:"name"`
+	From       string `json:"from,omitempty"`
+	Method     string `json:"method,omitempty"`
+	Level      int    `json:"level,omitempty"`
+}
+
+// EvolutionLine returns every species in the evolution family of the given
+// species, starting from its first stage, with branches in data order.
+func (d *Dataset) EvolutionLine(nationalID int) []EvolutionStep {
+	root := nationalID
+	for seen := map[int]bool{root: true}; ; {
+		from := d.Evolutions[root].From
+		if len(from) == 0 || seen[from[0].NationalID] {
+			break
+		}
+		root = from[0].NationalID
+		seen[root] = true
+	}
+
+	name := func(id int) string {
+		if pokemon, ok := d.Pokemon(id); ok {
+			return pokemon.Name
+		}
+		return strconv.Itoa(id)
+	}
+
+	line := []EvolutionStep{{Stage: 1, NationalID: root, Name: name(root)}}
+	seen := map[int]bool{root: true}
+	for i := 0; i < len(line); i++ {
+		for _, next := range d.Evolutions[line[i].NationalID].To {
+			if seen[next.NationalID] {
+				continue
+			}
+			seen[next.NationalID] = true
+			line = append(line, EvolutionStep{
+				Stage:      line[i].Stage + 1,
+				NationalID: next.NationalID,
+				Name:       name(next.NationalID),
+				From:       line[i].Name,
+				Method:     next.Method,
+				Level:      next.Level,
+			})
+		}
+	}
+	return line
+}
@@ -0,0 +1,90 @@
+package pokedata
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Query
+		wantErr bool
+	}{
+		{input: "", want: Query{}},
+		{input: "type:fire type:flying ability:blaze", want: Query{Types: []string{"fire", "flying"}, Ability: "blaze"}},
+		{input: "speed>100 total>=500", want: Query{Stats: []StatFilter{{"speed", ">", 100}, {"total", ">=", 500}}}},
+		{input: "stage:final sort:-speed", want: Query{FullyEvolved: true, SortBy: "speed", Descending: true}},
+		{input: "limit:10 offset:5", want: Query{Limit: 10, Offset: 5}},
+		{input: "limit:10 page:1", want: Query{Limit: 10}},
+		{input: "limit:10 page:3", want: Query{Limit: 10, Offset: 20}},
+		{input: "page:2", wantErr: true},
+		{input: "limit:-1", wantErr: true},
+		{input: "offset:-5", wantErr: true},
+		{input: "limit:10 page:-1", wantErr: true},
+		{input: fmt.Sprintf("limit:%d page:4", math.MaxInt/2), wantErr: true},
+		{input: fmt.Sprintf("limit:10 page:%d", math.MaxInt), wantErr: true},
+		{input: fmt.Sprintf("limit:%d page:2", math.MaxInt), want: Query{Limit: math.MaxInt, Offset: math.MaxInt}},
+		{input: "limit:ten", wantErr: true},
+		{input: "sort:weight", wantErr: true},
+		{input: "color:red", wantErr: true},
+		{input: "luck>5", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuery(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseQuery(%q) = %+v, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSearchPaging(t *testing.T) {
+	d := &Dataset{}
+	for id := 1; id <= 5; id++ {
+		d.Pokemons = append(d.Pokemons, Pokemon{NationalID: id})
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []int
+	}{
+		{name: "everything", query: Query{}, want: []int{1, 2, 3, 4, 5}},
+		{name: "first page", query: Query{Limit: 2}, want: []int{1, 2}},
+		{name: "middle page", query: Query{Offset: 2, Limit: 2}, want: []int{3, 4}},
+		{name: "short last page", query: Query{Offset: 4, Limit: 2}, want: []int{5}},
+		{name: "past the end", query: Query{Offset: 10, Limit: 2}, want: []int{}},
+		{name: "negative offset", query: Query{Offset: -3, Limit: 2}, want: []int{1, 2}},
+		// Offset+Limit would overflow.
+		{name: "huge limit", query: Query{Offset: 3, Limit: math.MaxInt}, want: []int{4, 5}},
+		{name: "huge offset", query: Query{Offset: math.MaxInt, Limit: math.MaxInt}, want: []int{}},
+		{name: "descending", query: Query{Descending: true, Limit: 2}, want: []int{5, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := d.Search(tt.query)
+			if result.Total != len(d.Pokemons) {
+				t.Errorf("Total = %d, want %d", result.Total, len(d.Pokemons))
+			}
+			got := []int{}
+			for _, pokemon := range result.Pokemons {
+				got = append(got, pokemon.NationalID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
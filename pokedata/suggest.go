@@ -0,0 +1,113 @@
+package pokedata
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggest ranks the candidates that look like what the user meant by input:
+// names starting with the input first, then names within a few typos of it.
+// At most max names are returned.
+func Suggest(candidates []string, input string, max int) []string {
+	key := normalizeName(input)
+	if key == "" {
+		return nil
+	}
+	// Allow roughly one typo per three letters, and never more than three
+	maxDistance := len(key)/3 + 1
+	if maxDistance > 3 {
+		maxDistance = 3
+	}
+
+	type match struct {
+		name  string
+		score int
+	}
+	var matches []match
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		name := normalizeName(candidate)
+		switch {
+		case name == key:
+			matches = append(matches, match{candidate, -1})
+		case strings.HasPrefix(name, key):
+			matches = append(matches, match{candidate, 0})
+		default:
+			if distance := editDistance(key, name); distance <= maxDistance {
+				matches = append(matches, match{candidate, distance})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		if len(matches[i].name) != len(matches[j].name) {
+			return len(matches[i].name) < len(matches[j].name)
+		}
+		return matches[i].name < matches[j].name
+	})
+	if len(matches) > max {
+		matches = matches[:max]
+	}
+
+	suggestions := make([]string, len(matches))
+	for i, m := range matches {
+		suggestions[i] = m.name
+	}
+	return suggestions
+}
+
+// SuggestPokemon returns species names close to a mistyped name.
+func (d *Dataset) SuggestPokemon(input string, max int) []string {
+	names := make([]string, len(d.Pokemons))
+	for i, pokemon := range d.Pokemons {
+		names[i] = pokemon.Name
+	}
+	return Suggest(names, input, max)
+}
+
+// SuggestMoves returns move names close to a mistyped move name or
+// identifier.
+func (d *Dataset) SuggestMoves(input string, max int) []string {
+	names := make([]string, 0, len(d.Moves))
+	for _, move := range d.Moves {
+		names = append(names, move.Name)
+	}
+	sort.Strings(names)
+	return Suggest(names, input, max)
+}
+
+// normalizeName makes "Fire Punch", "fire-punch" and "Fire-punch" compare
+// equal.
+func normalizeName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,69 @@
+package pokedata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"pikachu", "pikachu", 0},
+		{"pikachu", "pikachi", 1},
+		{"pikachu", "pikachuu", 1},
+		{"pikchu", "pikachu", 1},
+		{"kitten", "sitting", 3},
+		{"flabébé", "flabebe", 2},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"Pikachu", "Raichu", "Pichu", "Charmander", "Charmeleon", "Charizard", "Mr. Mime", "Fire Punch"}
+
+	tests := []struct {
+		name  string
+		input string
+		max   int
+		want  []string
+	}{
+		{name: "typo", input: "pikachi", max: 5, want: []string{"Pikachu", "Pichu"}},
+		{name: "missing letter", input: "charzard", max: 5, want: []string{"Charizard"}},
+		// An exact match comes before anything merely close.
+		{name: "case", input: "PIKACHU", max: 5, want: []string{"Pikachu", "Pichu"}},
+		{name: "separators", input: "fire-punch", max: 5, want: []string{"Fire Punch"}},
+		{name: "prefix before typos", input: "charm", max: 5, want: []string{"Charmander", "Charmeleon"}},
+		{name: "limit", input: "char", max: 2, want: []string{"Charizard", "Charmander"}},
+		{name: "no close match", input: "bulbasaur", max: 5, want: []string{}},
+		{name: "empty input", input: "  ", max: 5, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Suggest(names, tt.input, tt.max); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestPokemon(t *testing.T) {
+	d := &Dataset{Pokemons: []Pokemon{{Name: "Bulbasaur"}, {Name: "Ivysaur"}, {Name: "Venusaur"}}}
+
+	if got, want := d.SuggestPokemon("bulbsaur", 3), []string{"Bulbasaur"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestPokemon(bulbsaur) = %q, want %q", got, want)
+	}
+	if got := d.SuggestPokemon("mewtwo", 3); len(got) != 0 {
+		t.Errorf("SuggestPokemon(mewtwo) = %q, want none", got)
+	}
+}
@@ -0,0 +1,226 @@
+package pokedata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxTeamSize is the most Pokémon a team can have, in an analysis or a
+// battle.
+const MaxTeamSize = 6
+
+// TeamMember is a species on a team and, optionally, the moves it knows.
+// Members without moves are assumed to attack with their own types.
+type TeamMember struct {
+	NationalID int
+	Moves      []string
+}
+
+// TeamAnalysis describes how well a team defends against and attacks each
+// type.
+type TeamAnalysis struct {
+	Members  []string       `json:"members"`
+	Defense  []TypeDefense  `json:"defense"`
+	Coverage []TypeCoverage `json:"coverage"`
+	// SharedWeaknesses are attacking types that several members are weak to
+	// and that more members are weak to than resist.
+	SharedWeaknesses []string `json:"shared_weaknesses"`
+	// Uncovered are defending types none of the team's attacks hit super
+	// effectively.
+	Uncovered   []string         `json:"uncovered"`
+	Suggestions []TeamSuggestion `json:"suggestions"`
+}
+
+// TypeDefense is how the team fares against attacks of one type.
+type TypeDefense struct {
+	AttackType string   `json:"attack_type"`
+	Weak       []string `json:"weak"`
+	Resistant  []string `json:"resistant"`
+	Immune     []string `json:"immune"`
+}
+
+// TypeCoverage is the best damage the team can deal to a defending type, and
+// with which attack types.
+type TypeCoverage struct {
+	DefenseType string   `json:"defense_type"`
+	Multiplier  float64  `json:"multiplier"`
+	AttackTypes []string `json:"attack_types"`
+}
+
+// TeamSuggestion is a species that would patch some of the team's gaps.
+type TeamSuggestion struct {
+	NationalID int      `json:"national_id"`
+	Name       string   `json:"name"`
+	Resists    []string `json:"resists"`
+	Covers     []string `json:"covers"`
+}
+
+const maxTeamSuggestions = 5
+
+// AnalyzeTeam reports the defensive weaknesses and resistances of a team, its
+// offensive coverage, its shared weaknesses and species that patch them.
+func (d *Dataset) AnalyzeTeam(members []TeamMember) (TeamAnalysis, error) {
+	if len(members) == 0 || len(members) > MaxTeamSize {
+		return TeamAnalysis{}, fmt.Errorf("a team has between 1 and %d members", MaxTeamSize)
+	}
+
+	var team []*Pokemon
+	inTeam := make(map[int]bool)
+	attackTypes := make(map[string]bool)
+	for _, member := range members {
+		pokemon, ok := d.Pokemon(member.NationalID)
+		if !ok {
+			return TeamAnalysis{}, fmt.Errorf("unknown Pokémon %d", member.NationalID)
+		}
+		team = append(team, pokemon)
+		inTeam[pokemon.NationalID] = true
+
+		if len(member.Moves) == 0 {
+			for _, t := range pokemon.Types {
+				attackTypes[t.Name] = true
+			}
+			continue
+		}
+		for _, name := range member.Moves {
+			move, ok := d.MoveByName(name)
+			if !ok {
+				return TeamAnalysis{}, fmt.Errorf("unknown move %q", name)
+			}
+			// Status moves don't deal damage, so they cover nothing.
+			if move.Damaging() {
+				attackTypes[move.TypeName] = true
+			}
+		}
+	}
+
+	analysis := TeamAnalysis{
+		SharedWeaknesses: []string{},
+		Uncovered:        []string{},
+		Suggestions:      []TeamSuggestion{},
+	}
+	for _, pokemon := range team {
+		analysis.Members = append(analysis.Members, pokemon.Name)
+	}
+
+	types := d.TypeNames()
+	for _, attackType := range types {
+		defense := TypeDefense{AttackType: attackType, Weak: []string{}, Resistant: []string{}, Immune: []string{}}
+		for _, pokemon := range team {
+			switch value := d.Effectiveness(attackType, pokemon.TypeNames()...); {
+			case value == 0:
+				defense.Immune = append(defense.Immune, pokemon.Name)
+			case value < 1:
+				defense.Resistant = append(defense.Resistant, pokemon.Name)
+			case value > 1:
+				defense.Weak = append(defense.Weak, pokemon.Name)
+			}
+		}
+		analysis.Defense = append(analysis.Defense, defense)
+		if len(defense.Weak) >= 2 && len(defense.Weak) > len(defense.Resistant)+len(defense.Immune) {
+			analysis.SharedWeaknesses = append(analysis.SharedWeaknesses, attackType)
+		}
+	}
+
+	for _, defenseType := range types {
+		coverage := TypeCoverage{DefenseType: defenseType, AttackTypes: []string{}}
+		for attackType := range attackTypes {
+			value := d.Effectiveness(attackType, defenseType)
+			switch {
+			case value > coverage.Multiplier:
+				coverage.Multiplier = value
+				coverage.AttackTypes = []string{attackType}
+			case value == coverage.Multiplier:
+				coverage.AttackTypes = append(coverage.AttackTypes, attackType)
+			}
+		}
+		sort.Strings(coverage.AttackTypes)
+		analysis.Coverage = append(analysis.Coverage, coverage)
+		if coverage.Multiplier <= 1 {
+			analysis.Uncovered = append(analysis.Uncovered, defenseType)
+		}
+	}
+
+	analysis.Suggestions = d.suggestTeamMembers(inTeam, analysis.SharedWeaknesses, analysis.Uncovered)
+	return analysis, nil
+}
+
+// suggestTeamMembers ranks the species outside the team by how many of the
+// shared weaknesses they resist and uncovered types their own types hit super
+// effectively, breaking ties by base stat total.
+func (d *Dataset) suggestTeamMembers(inTeam map[int]bool, weaknesses, uncovered []string) []TeamSuggestion {
+	type candidate struct {
+		suggestion TeamSuggestion
+		total      int
+	}
+	var candidates []candidate
+	for i := range d.Pokemons {
+		pokemon := &d.Pokemons[i]
+		if inTeam[pokemon.NationalID] {
+			continue
+		}
+
+		suggestion := TeamSuggestion{NationalID: pokemon.NationalID, Name: pokemon.Name, Resists: []string{}, Covers: []string{}}
+		for _, attackType := range weaknesses {
+			if d.Effectiveness(attackType, pokemon.TypeNames()...) < 1 {
+				suggestion.Resists = append(suggestion.Resists, attackType)
+			}
+		}
+		for _, defenseType := range uncovered {
+			for _, t := range pokemon.Types {
+				if d.Effectiveness(t.Name, defenseType) > 1 {
+					suggestion.Covers = append(suggestion.Covers, defenseType)
+					break
+				}
+			}
+		}
+		if len(suggestion.Resists)+len(suggestion.Covers) == 0 {
+			continue
+		}
+		total, _ := pokemon.Stat("total")
+		candidates = append(candidates, candidate{suggestion, total})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].suggestion, candidates[j].suggestion
+		if scoreA, scoreB := len(a.Resists)+len(a.Covers), len(b.Resists)+len(b.Covers); scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		return candidates[i].total > candidates[j].total
+	})
+	if len(candidates) > maxTeamSuggestions {
+		candidates = candidates[:maxTeamSuggestions]
+	}
+
+	suggestions := []TeamSuggestion{}
+	for _, c := range candidates {
+		suggestions = append(suggestions, c.suggestion)
+	}
+	return suggestions
+}
+
+// MoveByName returns the move with the given name or identifier.
+func (d *Dataset) MoveByName(name string) (Move, bool) {
+	for _, move := range d.Moves {
+		if moveNameMatches(move, name) {
+			return move, true
+		}
+	}
+	return Move{}, false
+}
+
+// TypeNames lists every type any species has, sorted.
+func (d *Dataset) TypeNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, pokemon := range d.Pokemons {
+		for _, t := range pokemon.Types {
+			if name := strings.ToLower(t.Name); !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
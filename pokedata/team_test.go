@@ -0,0 +1,113 @@
+package pokedata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func teamDataset() *Dataset {
+	d := testDataset(
+		Pokemon{NationalID: 4, Name: "Charmander", Types: types("fire"), HP: 39},
+		Pokemon{NationalID: 7, Name: "Squirtle", Types: types("water"), HP: 44},
+		Pokemon{NationalID: 23, Name: "Ekans", Types: types("poison"), HP: 35},
+		Pokemon{NationalID: 37, Name: "Vulpix", Types: types("fire"), HP: 38},
+		Pokemon{NationalID: 114, Name: "Tangela", Types: types("grass"), HP: 65},
+	)
+	d.Chart = StandardTypeChart()
+
+	d.Moves[1] = Move{ID: 1, Identifier: "ember", Name: "Ember", TypeName: "fire", Power: intPtr(40), DamageClass: DamageClassSpecial}
+	d.Moves[2] = Move{ID: 2, Identifier: "growl", Name: "Growl", TypeName: "normal", DamageClass: DamageClassStatus}
+	d.Moves[3] = Move{ID: 3, Identifier: "water-gun", Name: "Water Gun", TypeName: "water", Power: intPtr(40), DamageClass: DamageClassSpecial}
+	return d
+}
+
+func TestAnalyzeTeamErrors(t *testing.T) {
+	d := teamDataset()
+	tests := []struct {
+		name    string
+		members []TeamMember
+	}{
+		{"empty", nil},
+		{"too big", []TeamMember{{NationalID: 4}, {NationalID: 7}, {NationalID: 23}, {NationalID: 37}, {NationalID: 114}, {NationalID: 4}, {NationalID: 7}}},
+		{"unknown Pokémon", []TeamMember{{NationalID: 151}}},
+		{"unknown move", []TeamMember{{NationalID: 4, Moves: []string{"Flamethrower"}}}},
+	}
+	for _, tt := range tests {
+		if _, err := d.AnalyzeTeam(tt.members); err == nil {
+			t.Errorf("%s: AnalyzeTeam succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestAnalyzeTeam(t *testing.T) {
+	analysis, err := teamDataset().AnalyzeTeam([]TeamMember{{NationalID: 4}, {NationalID: 37}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"Charmander", "Vulpix"}; !reflect.DeepEqual(analysis.Members, want) {
+		t.Errorf("members = %v, want %v", analysis.Members, want)
+	}
+	wantDefense := []TypeDefense{
+		{AttackType: "fire", Weak: []string{}, Resistant: []string{"Charmander", "Vulpix"}, Immune: []string{}},
+		{AttackType: "grass", Weak: []string{}, Resistant: []string{"Charmander", "Vulpix"}, Immune: []string{}},
+		{AttackType: "poison", Weak: []string{}, Resistant: []string{}, Immune: []string{}},
+		{AttackType: "water", Weak: []string{"Charmander", "Vulpix"}, Resistant: []string{}, Immune: []string{}},
+	}
+	if !reflect.DeepEqual(analysis.Defense, wantDefense) {
+		t.Errorf("defense = %+v, want %+v", analysis.Defense, wantDefense)
+	}
+	if want := []string{"water"}; !reflect.DeepEqual(analysis.SharedWeaknesses, want) {
+		t.Errorf("shared weaknesses = %v, want %v", analysis.SharedWeaknesses, want)
+	}
+
+	// Without moves the team attacks with fire only.
+	wantCoverage := []TypeCoverage{
+		{DefenseType: "fire", Multiplier: 0.5, AttackTypes: []string{"fire"}},
+		{DefenseType: "grass", Multiplier: 2, AttackTypes: []string{"fire"}},
+		{DefenseType: "poison", Multiplier: 1, AttackTypes: []string{"fire"}},
+		{DefenseType: "water", Multiplier: 0.5, AttackTypes: []string{"fire"}},
+	}
+	if !reflect.DeepEqual(analysis.Coverage, wantCoverage) {
+		t.Errorf("coverage = %+v, want %+v", analysis.Coverage, wantCoverage)
+	}
+	if want := []string{"fire", "poison", "water"}; !reflect.DeepEqual(analysis.Uncovered, want) {
+		t.Errorf("uncovered = %v, want %v", analysis.Uncovered, want)
+	}
+
+	// Tangela and Squirtle both patch two gaps; Tangela has the higher total.
+	// Ekans patches nothing.
+	wantSuggestions := []TeamSuggestion{
+		{NationalID: 114, Name: "Tangela", Resists: []string{"water"}, Covers: []string{"water"}},
+		{NationalID: 7, Name: "Squirtle", Resists: []string{"water"}, Covers: []string{"fire"}},
+	}
+	if !reflect.DeepEqual(analysis.Suggestions, wantSuggestions) {
+		t.Errorf("suggestions = %+v, want %+v", analysis.Suggestions, wantSuggestions)
+	}
+}
+
+func TestAnalyzeTeamMoves(t *testing.T) {
+	d := teamDataset()
+
+	// Growl deals no damage, so it covers nothing.
+	analysis, err := d.AnalyzeTeam([]TeamMember{{NationalID: 4, Moves: []string{"growl"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, coverage := range analysis.Coverage {
+		if coverage.Multiplier != 0 || len(coverage.AttackTypes) != 0 {
+			t.Errorf("coverage with only Growl = %+v, want nothing", coverage)
+		}
+	}
+
+	analysis, err = d.AnalyzeTeam([]TeamMember{{NationalID: 4, Moves: []string{"Water Gun", "Growl"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (TypeCoverage{DefenseType: "fire", Multiplier: 2, AttackTypes: []string{"water"}}); !reflect.DeepEqual(analysis.Coverage[0], want) {
+		t.Errorf("coverage of fire = %+v, want %+v", analysis.Coverage[0], want)
+	}
+	if want := []string{"grass", "poison", "water"}; !reflect.DeepEqual(analysis.Uncovered, want) {
+		t.Errorf("uncovered = %v, want %v", analysis.Uncovered, want)
+	}
+}
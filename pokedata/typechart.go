@@ -0,0 +1,223 @@
+package pokedata
+
+import (
+	"sort"
+	"strings"
+)
+
+// TypeChart holds how much damage each attacking type deals to each
+// defending type, keyed by attacking type and then defending type, both lower
+// case. Pairs that aren't in the chart deal normal damage.
+type TypeChart map[string]map[string]float64
+
+// ChartMismatch is a species whose multiplier in MonsterType.json disagrees
+// with the one the type chart gives for its types.
+type ChartMismatch struct {
+	NationalID int     `json:"national_id"`
+	Name       string  `json:"name"`
+	AttackType string  `json:"attack_type"`
+	Recorded   float64 `json:"recorded"`
+	Expected   float64 `json:"expected"`
+}
+
+// Effectiveness is how much damage a move of attackType deals to a Pokémon
+// with the given types: the product of the chart entry for each of them.
+func (c TypeChart) Effectiveness(attackType string, defenderTypes ...string) float64 {
+	multiplier := 1.0
+	row := c[strings.ToLower(attackType)]
+	for _, defenseType := range defenderTypes {
+		if value, ok := row[strings.ToLower(defenseType)]; ok {
+			multiplier *= value
+		}
+	}
+	return multiplier
+}
+
+func (c TypeChart) set(attackType, defenseType string, value float64) {
+	if c[attackType] == nil {
+		c[attackType] = make(map[string]float64)
+	}
+	c[attackType][defenseType] = value
+}
+
+// Effectiveness is how much damage a move of attackType deals to a Pokémon
+// with the given types, according to the dataset's type chart.
+func (d *Dataset) Effectiveness(attackType string, defenderTypes ...string) float64 {
+	return d.Chart.Effectiveness(attackType, defenderTypes...)
+}
+
+// PokemonEffectiveness is how much damage a move of attackType deals to the
+// species with the given national ID.
+func (d *Dataset) PokemonEffectiveness(attackType string, nationalID int) float64 {
+	pokemon, ok := d.Pokemon(nationalID)
+	if !ok {
+		return 1
+	}
+	return d.Effectiveness(attackType, pokemon.TypeNames()...)
+}
+
+// TypeNames returns the species' types in lower case.
+func (p *Pokemon) TypeNames() []string {
+	var names []string
+	for _, t := range p.Types {
+		names = append(names, strings.ToLower(t.Name))
+	}
+	return names
+}
+
+// DeriveTypeChart reconstructs the type chart from MonsterType.json. Each
+// defending type's column is read from the species that have only that type,
+// taking the most common multiplier when they disagree; types with no
+// single-type species are solved from dual types whose other type is known.
+// The chart is then checked against every dual-type species, and the
+// multipliers that don't match are returned. The dataset doesn't use the
+// result; it tells whether MonsterType.json is consistent with itself.
+func (d *Dataset) DeriveTypeChart() (TypeChart, []ChartMismatch) {
+	types := d.TypeNames()
+	chart := make(TypeChart)
+	var mismatches []ChartMismatch
+
+	known := make(map[string]bool)
+	for _, defenseType := range types {
+		var singles []*Pokemon
+		for i := range d.Pokemons {
+			if names := d.Pokemons[i].TypeNames(); len(names) == 1 && names[0] == defenseType {
+				singles = append(singles, &d.Pokemons[i])
+			}
+		}
+		if len(singles) == 0 {
+			continue
+		}
+		known[defenseType] = true
+		for _, attackType := range types {
+			votes := make(map[float64]int)
+			for _, pokemon := range singles {
+				votes[d.recordedMultiplier(pokemon.NationalID, attackType)]++
+			}
+			value := mostCommon(votes)
+			chart.set(attackType, defenseType, value)
+			for _, pokemon := range singles {
+				if recorded := d.recordedMultiplier(pokemon.NationalID, attackType); recorded != value {
+					mismatches = append(mismatches, ChartMismatch{pokemon.NationalID, pokemon.Name, attackType, recorded, value})
+				}
+			}
+		}
+	}
+
+	for _, defenseType := range types {
+		if known[defenseType] {
+			continue
+		}
+		for _, attackType := range types {
+			votes := make(map[float64]int)
+			for i := range d.Pokemons {
+				other, ok := otherType(d.Pokemons[i].TypeNames(), defenseType)
+				if !ok || !known[other] {
+					continue
+				}
+				if partner := chart.Effectiveness(attackType, other); partner != 0 {
+					votes[d.recordedMultiplier(d.Pokemons[i].NationalID, attackType)/partner]++
+				}
+			}
+			if len(votes) > 0 {
+				chart.set(attackType, defenseType, mostCommon(votes))
+			}
+		}
+	}
+
+	for i := range d.Pokemons {
+		pokemon := &d.Pokemons[i]
+		names := pokemon.TypeNames()
+		if len(names) != 2 {
+			continue
+		}
+		for _, attackType := range types {
+			expected := chart.Effectiveness(attackType, names...)
+			if recorded := d.recordedMultiplier(pokemon.NationalID, attackType); recorded != expected {
+				mismatches = append(mismatches, ChartMismatch{pokemon.NationalID, pokemon.Name, attackType, recorded, expected})
+			}
+		}
+	}
+	return chart, mismatches
+}
+
+// recordedMultiplier is the multiplier MonsterType.json gives a species
+// against an attacking type. Types it leaves out are neutral.
+func (d *Dataset) recordedMultiplier(nationalID int, attackType string) float64 {
+	mult := d.Types[nationalID]
+	return multiplierAgainst(&mult, attackType)
+}
+
+// mostCommon returns the value with the most votes, preferring the one
+// closest to neutral on a tie so that the result doesn't depend on map order.
+func mostCommon(votes map[float64]int) float64 {
+	var values []float64
+	for value := range votes {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if votes[values[i]] != votes[values[j]] {
+			return votes[values[i]] > votes[values[j]]
+		}
+		return distanceFromNeutral(values[i]) < distanceFromNeutral(values[j])
+	})
+	if len(values) == 0 {
+		return 1
+	}
+	return values[0]
+}
+
+func distanceFromNeutral(value float64) float64 {
+	if value < 1 {
+		return 1 - value
+	}
+	return value - 1
+}
+
+// otherType returns the type in a dual type that isn't typeName.
+func otherType(names []string, typeName string) (string, bool) {
+	if len(names) != 2 {
+		return "", false
+	}
+	switch typeName {
+	case names[0]:
+		return names[1], true
+	case names[1]:
+		return names[0], true
+	}
+	return "", false
+}
+
+// standardChart lists the attacking and defending type pairs that don't deal
+// normal damage, as of the sixth generation.
+var standardChart = map[string]map[string]float64{
+	"normal":   {"rock": 0.5, "ghost": 0, "steel": 0.5},
+	"fire":     {"fire": 0.5, "water": 0.5, "grass": 2, "ice": 2, "bug": 2, "rock": 0.5, "dragon": 0.5, "steel": 2},
+	"water":    {"fire": 2, "water": 0.5, "grass": 0.5, "ground": 2, "rock": 2, "dragon": 0.5},
+	"electric": {"water": 2, "electric": 0.5, "grass": 0.5, "ground": 0, "flying": 2, "dragon": 0.5},
+	"grass":    {"fire": 0.5, "water": 2, "grass": 0.5, "poison": 0.5, "ground": 2, "flying": 0.5, "bug": 0.5, "rock": 2, "dragon": 0.5, "steel": 0.5},
+	"ice":      {"fire": 0.5, "water": 0.5, "grass": 2, "ice": 0.5, "ground": 2, "flying": 2, "dragon": 2, "steel": 0.5},
+	"fighting": {"normal": 2, "ice": 2, "poison": 0.5, "flying": 0.5, "psychic": 0.5, "bug": 0.5, "rock": 2, "ghost": 0, "dark": 2, "steel": 2, "fairy": 0.5},
+	"poison":   {"grass": 2, "poison": 0.5, "ground": 0.5, "rock": 0.5, "ghost": 0.5, "steel": 0, "fairy": 2},
+	"ground":   {"fire": 2, "electric": 2, "grass": 0.5, "poison": 2, "flying": 0, "bug": 0.5, "rock": 2, "steel": 2},
+	"flying":   {"electric": 0.5, "grass": 2, "fighting": 2, "bug": 2, "rock": 0.5, "steel": 0.5},
+	"psychic":  {"fighting": 2, "poison": 2, "psychic": 0.5, "dark": 0, "steel": 0.5},
+	"bug":      {"fire": 0.5, "grass": 2, "fighting": 0.5, "poison": 0.5, "flying": 0.5, "psychic": 2, "ghost": 0.5, "dark": 2, "steel": 0.5, "fairy": 0.5},
+	"rock":     {"fire": 2, "ice": 2, "fighting": 0.5, "ground": 0.5, "flying": 2, "bug": 2, "steel": 0.5},
+	"ghost":    {"normal": 0, "psychic": 2, "ghost": 2, "dark": 0.5},
+	"dragon":   {"dragon": 2, "steel": 0.5, "fairy": 0},
+	"dark":     {"fighting": 0.5, "psychic": 2, "ghost": 2, "dark": 0.5, "fairy": 0.5},
+	"steel":    {"fire": 0.5, "water": 0.5, "electric": 0.5, "ice": 2, "rock": 2, "steel": 0.5, "fairy": 2},
+	"fairy":    {"fire": 0.5, "fighting": 2, "poison": 0.5, "dragon": 2, "dark": 2, "steel": 0.5},
+}
+
+// StandardTypeChart returns a copy of the sixth generation type chart.
+func StandardTypeChart() TypeChart {
+	chart := make(TypeChart)
+	for attackType, row := range standardChart {
+		for defenseType, value := range row {
+			chart.set(attackType, defenseType, value)
+		}
+	}
+	return chart
+}
@@ -0,0 +1,93 @@
+package pokedata
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// recorded builds MonsterType.json multipliers, leaving out neutral ones the
+// way the crawler does.
+func recorded(id int, multipliers map[string]float64) Mult {
+	mult := Mult{ID: id}
+	for attackType, value := range multipliers {
+		mult.MonsterTypes = append(mult.MonsterTypes, MonsterType{attackType, fmt.Sprintf("%gx", value)})
+	}
+	return mult
+}
+
+// chartDataset has fire, water and grass single types and flying only in
+// dual types, with multipliers from the standard chart except for Vulpix's
+// weakness to water and Pelipper's weakness to grass.
+func chartDataset() *Dataset {
+	d := testDataset(
+		Pokemon{NationalID: 4, Name: "Charmander", Types: types("fire")},
+		Pokemon{NationalID: 6, Name: "Charizard", Types: types("fire", "flying")},
+		Pokemon{NationalID: 7, Name: "Squirtle", Types: types("water")},
+		Pokemon{NationalID: 37, Name: "Vulpix", Types: types("fire")},
+		Pokemon{NationalID: 77, Name: "Ponyta", Types: types("fire")},
+		Pokemon{NationalID: 114, Name: "Tangela", Types: types("grass")},
+		Pokemon{NationalID: 279, Name: "Pelipper", Types: types("water", "flying")},
+		Pokemon{NationalID: 357, Name: "Tropius", Types: types("grass", "flying")},
+	)
+	fire := map[string]float64{"fire": 0.5, "water": 2, "grass": 0.5}
+	d.Types[4] = recorded(4, fire)
+	d.Types[6] = recorded(6, map[string]float64{"fire": 0.5, "water": 2, "grass": 0.25})
+	d.Types[7] = recorded(7, map[string]float64{"fire": 0.5, "water": 0.5, "grass": 2})
+	d.Types[37] = recorded(37, map[string]float64{"fire": 0.5, "grass": 0.5})
+	d.Types[77] = recorded(77, fire)
+	d.Types[114] = recorded(114, map[string]float64{"fire": 2, "water": 0.5, "grass": 0.5, "flying": 2})
+	d.Types[279] = recorded(279, map[string]float64{"fire": 0.5, "water": 0.5, "grass": 2})
+	d.Types[357] = recorded(357, map[string]float64{"fire": 2, "water": 0.5, "grass": 0.25, "flying": 2})
+	return d
+}
+
+func TestDeriveTypeChart(t *testing.T) {
+	chart, mismatches := chartDataset().DeriveTypeChart()
+
+	standard := StandardTypeChart()
+	for _, attackType := range []string{"fire", "water", "grass", "flying"} {
+		for _, defenseType := range []string{"fire", "water", "grass", "flying"} {
+			got, want := chart.Effectiveness(attackType, defenseType), standard.Effectiveness(attackType, defenseType)
+			if got != want {
+				t.Errorf("%s against %s = %v, want %v", attackType, defenseType, got, want)
+			}
+		}
+	}
+	if got, want := chart.Effectiveness("grass", "grass", "flying"), 0.25; got != want {
+		t.Errorf("grass against grass/flying = %v, want %v", got, want)
+	}
+
+	// Vulpix is outvoted by the other fire types, and Pelipper by the other
+	// flying dual types.
+	want := []ChartMismatch{
+		{NationalID: 37, Name: "Vulpix", AttackType: "water", Recorded: 1, Expected: 2},
+		{NationalID: 279, Name: "Pelipper", AttackType: "grass", Recorded: 2, Expected: 1},
+	}
+	if !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("mismatches = %+v, want %+v", mismatches, want)
+	}
+}
+
+func TestEffectiveness(t *testing.T) {
+	chart := StandardTypeChart()
+	tests := []struct {
+		attackType string
+		defender   []string
+		want       float64
+	}{
+		{"fire", []string{"grass"}, 2},
+		{"Fire", []string{"Grass"}, 2},
+		{"fire", []string{"grass", "steel"}, 4},
+		{"fire", []string{"water", "rock"}, 0.25},
+		{"electric", []string{"water", "ground"}, 0},
+		{"normal", []string{"normal"}, 1},
+		{"normal", nil, 1},
+		{"shadow", []string{"normal"}, 1},
+	}
+	for _, tt := range tests {
+		if got := chart.Effectiveness(tt.attackType, tt.defender...); got != tt.want {
+			t.Errorf("Effectiveness(%s, %v) = %v, want %v", tt.attackType, tt.defender, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package pokedata loads the crawled Pokémon data in data/*.json and gives
+// the server, the Pokédex tool and other services one shared model of it.
+package pokedata
+
+import (
+	"strconv"
+	"strings"
+)
+
+type ListMapObject struct {
+	Name        string `json:"name"`
+	ResourceURI string `json:"resource_uri,omitempty"`
+}
+
+// ResourceID reads the ID at the end of the resource URI, such as 15 for
+// "/api/v1/description/15/".
+func (o ListMapObject) ResourceID() (int, bool) {
+	uri := strings.TrimSuffix(o.ResourceURI, "/")
+	id, err := strconv.Atoi(uri[strings.LastIndex(uri, "/")+1:])
+	return id, err == nil
+}
+
+// Pokemon is a species from baseInfo.json.
+type Pokemon struct {
+	Descriptions    []ListMapObject `json:"descriptions"`
+	Types           []ListMapObject `json:"types"`
+	Abilities       []ListMapObject `json:"abilities"`
+	Attack          int             `json:"attack"`
+	Defense         int             `json:"defense"`
+	Speed           int             `json:"speed"`
+	SpAtk           int             `json:"sp_atk"`
+	SpDef           int             `json:"sp_def"`
+	HP              int             `json:"hp"`
+	Weight          string          `json:"weight"`
+	Height          string          `json:"height"`
+	NationalID      int             `json:"national_id"`
+	MaleFemaleRatio string          `json:"male_female_ratio"`
+	CatchRate       int             `json:"catch_rate"`
+	ID              string          `json:"_id"`
+	Name            string          `json:"name"`
+}
+
+// AdditionalInfo is a species' EV yield, breeding data and category from
+// stats.json.
+type AdditionalInfo struct {
+	SpecialAttackEV  int `json:"specialAttackEV"`
+	HPEV             int `json:"hpEV"`
+	HatchSteps       int `json:"hatchSteps"`
+	DefenseEV        int `json:"defenseEV"`
+	AttackEV         int `json:"attackEV"`
+	SpecialDefenseEV int `json:"specialDefenseEV"`
+	SpeedEV          int `json:"speedEV"`
+	// GenderRatio is the percentage of males, or "N/A" for genderless
+	// species.
+	GenderRatio  interface{} `json:"genderRatio"`
+	Species      string      `json:"species"`
+	JapaneseName string      `json:"japaneseName"`
+	HepburnName  string      `json:"Guranburu"`
+	EggGroups    string      `json:"eggGroups"`
+	ID           string      `json:"_id"`
+	Rev          string      `json:"_rev,omitempty"`
+}
+
+// Description is a flavor text from MonsterDescription.json. Its ID is the
+// number at the end of the resource URIs in baseInfo.json that link to it.
+// Version is the game version it's from, filled in from that link by
+// Dataset.SpeciesDescriptions, as is Species when the text was written for
+// another species in the same evolution line.
+type Description struct {
+	Description string `json:"description"`
+	Version     string `json:"version,omitempty"`
+	Species     string `json:"species,omitempty"`
+	ID          string `json:"_id"`
+	Rev         string `json:"_rev,omitempty"`
+}
+
+type Evolution struct {
+	From []EvolutionDetail `json:"from"`
+	To   []EvolutionDetail `json:"to"`
+	ID   string            `json:"_id"`
+	Rev  string            `json:"_rev,omitempty"`
+}
+
+type EvolutionDetail struct {
+	NationalID int    `json:"nationalId"`
+	Name       string `json:"name"`
+	Method     string `json:"method"`
+	Level      int    `json:"level"`
+}
+
+type MonsterType struct {
+	Type       string `json:"type"`
+	Multiplier string `json:"multiplier"`
+}
+
+// Value parses a multiplier such as "2x" or "0.5x", treating anything
+// unreadable as neutral.
+func (t MonsterType) Value() float64 {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(t.Multiplier), "x"), 64)
+	if err != nil {
+		return 1
+	}
+	return value
+}
+
+// Mult is how much damage each attacking type does to a species, from
+// MonsterType.json.
+type Mult struct {
+	ID           int           `json:"id"`
+	MonsterTypes []MonsterType `json:"monster_types"`
+}
+
+// LearnedMove is a move a species can learn, from monsterMoves.json. Details
+// is filled in from moves.json when the species is looked up through a
+// Dataset, and is nil for moves missing from it.
+type LearnedMove struct {
+	LearnType string `json:"learn_type"`
+	Level     int    `json:"level"`
+	ID        int    `json:"id"`
+	Details   *Move  `json:"details,omitempty"`
+}
+
+type MonsterMoves struct {
+	Moves []LearnedMove `json:"moves"`
+	ID    string        `json:"_id"`
+	Rev   string        `json:"_rev,omitempty"`
+}
+
+// Experience is a species' base experience yield from exp.json.
+type Experience struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Exp  string `json:"exp"`
+}
+
+// Base returns the base experience as a number, or 0 if it isn't one.
+func (e Experience) Base() int {
+	exp, _ := strconv.Atoi(e.Exp)
+	return exp
+}
+
+// PokemonInfo gathers everything known about one species.
+type PokemonInfo struct {
+	Pokemon      *Pokemon        `json:"pokemon"`
+	Additional   *AdditionalInfo `json:"additional_info"`
+	Experience   *Experience     `json:"experience"`
+	Descriptions []Description   `json:"descriptions"`
+	Evolution    *Evolution      `json:"evolution"`
+	TypeInfo     *Mult           `json:"type_info"`
+	MonsterMoves *MonsterMoves   `json:"monster_moves"`
+}
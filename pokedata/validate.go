@@ -0,0 +1,602 @@
+package pokedata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The checks a Problem can come from.
+const (
+	// CheckSchema is a file, record or field that isn't shaped the way the
+	// crawler writes it.
+	CheckSchema = "schema"
+	// CheckID is an ID that is malformed or disagrees with another file
+	// about the species it names.
+	CheckID = "id"
+	// CheckDuplicate is an ID or name used by more than one record.
+	CheckDuplicate = "duplicate"
+	// CheckDangling is a reference to a species, move or description that
+	// doesn't exist.
+	CheckDangling = "dangling"
+	// CheckMissing is a species without a record in one of the files.
+	CheckMissing = "missing"
+	// CheckTypeChart is a type multiplier that disagrees with the type chart.
+	CheckTypeChart = "type_chart"
+)
+
+// Problem is one thing wrong with the data files.
+type Problem struct {
+	Check string `json:"check"`
+	File  string `json:"file"`
+	// Index is the position of the record in the file, or -1 when the
+	// problem is with the whole file.
+	Index   int    `json:"index"`
+	ID      string `json:"id,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	location := p.File
+	if p.Index >= 0 {
+		location += fmt.Sprintf("[%d]", p.Index)
+	}
+	if p.ID != "" {
+		location += " " + p.ID
+	}
+	if p.Field != "" {
+		location += " " + p.Field
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Check, location, p.Message)
+}
+
+// Kinds of JSON value a field may hold.
+const (
+	kindString = 1 << iota
+	kindNumber
+	kindArray
+	kindObject
+	kindNull
+	// kindNumeric is a string holding a number.
+	kindNumeric
+	// kindEmpty is an empty string, which the dumps use for missing numbers.
+	kindEmpty
+)
+
+var kindNames = []string{"a string", "a number", "an array", "an object", "null", "a numeric string", "an empty string"}
+
+type field struct {
+	name     string
+	kind     int
+	optional bool
+	// items are the fields of the objects in an array.
+	items []field
+}
+
+type dataFile struct {
+	name string
+	// id is the field holding the record's ID, which is zero-padded to
+	// width digits.
+	id    string
+	width int
+	// species is whether the ID is a national ID.
+	species bool
+	fields  []field
+	// decode decodes the file the way Load does.
+	decode func(data []byte) error
+}
+
+func decodeAs[T any](data []byte) error {
+	var records []T
+	return json.Unmarshal(data, &records)
+}
+
+var listMapFields = []field{
+	{name: "name", kind: kindString},
+	{name: "resource_uri", kind: kindString, optional: true},
+}
+
+var evolutionFields = []field{
+	{name: "nationalId", kind: kindNumber},
+	{name: "name", kind: kindString},
+	{name: "method", kind: kindString},
+	{name: "level", kind: kindNumber},
+}
+
+// dataFiles describe the files the crawler writes, in the order Load reads
+// them.
+var dataFiles = []dataFile{
+	{name: "baseInfo.json", id: "_id", species: true, decode: decodeAs[Pokemon], fields: []field{
+		{name: "descriptions", kind: kindArray, items: []field{
+			{name: "name", kind: kindString},
+			{name: "resource_uri", kind: kindString},
+		}},
+		{name: "types", kind: kindArray, items: listMapFields},
+		{name: "abilities", kind: kindArray, items: listMapFields},
+		{name: "attack", kind: kindNumber},
+		{name: "defense", kind: kindNumber},
+		{name: "speed", kind: kindNumber},
+		{name: "sp_atk", kind: kindNumber},
+		{name: "sp_def", kind: kindNumber},
+		{name: "hp", kind: kindNumber},
+		{name: "weight", kind: kindNumeric},
+		{name: "height", kind: kindNumeric},
+		{name: "national_id", kind: kindNumber},
+		{name: "male_female_ratio", kind: kindString},
+		{name: "catch_rate", kind: kindNumber},
+		{name: "_id", kind: kindNumeric},
+		{name: "name", kind: kindString},
+	}},
+	{name: "stats.json", id: "_id", width: 5, species: true, decode: decodeAs[AdditionalInfo], fields: []field{
+		{name: "specialAttackEV", kind: kindNumber},
+		{name: "hpEV", kind: kindNumber},
+		{name: "hatchSteps", kind: kindNumber},
+		{name: "defenseEV", kind: kindNumber},
+		{name: "attackEV", kind: kindNumber},
+		{name: "specialDefenseEV", kind: kindNumber},
+		{name: "speedEV", kind: kindNumber},
+		{name: "genderRatio", kind: kindNumber | kindString},
+		{name: "species", kind: kindString},
+		{name: "japaneseName", kind: kindString},
+		{name: "Guranburu", kind: kindString},
+		{name: "eggGroups", kind: kindString},
+		{name: "_id", kind: kindNumeric},
+		{name: "_rev", kind: kindString, optional: true},
+	}},
+	{name: "MonsterDescription.json", id: "_id", width: 7, decode: decodeAs[Description], fields: []field{
+		{name: "description", kind: kindString},
+		{name: "_id", kind: kindNumeric},
+		{name: "_rev", kind: kindString, optional: true},
+	}},
+	{name: "evolution.json", id: "_id", width: 5, species: true, decode: decodeAs[Evolution], fields: []field{
+		{name: "from", kind: kindArray | kindNull, items: evolutionFields},
+		{name: "to", kind: kindArray | kindNull, items: evolutionFields},
+		{name: "_id", kind: kindNumeric},
+		{name: "_rev", kind: kindString, optional: true},
+	}},
+	{name: "MonsterType.json", id: "id", species: true, decode: decodeAs[Mult], fields: []field{
+		{name: "id", kind: kindNumber},
+		{name: "monster_types", kind: kindArray, items: []field{
+			{name: "type", kind: kindString},
+			{name: "multiplier", kind: kindString},
+		}},
+	}},
+	{name: "monsterMoves.json", id: "_id", width: 5, species: true, decode: decodeAs[MonsterMoves], fields: []field{
+		{name: "moves", kind: kindArray, items: []field{
+			{name: "learn_type", kind: kindString},
+			{name: "level", kind: kindNumber},
+			{name: "id", kind: kindNumber},
+		}},
+		{name: "_id", kind: kindNumeric},
+		{name: "_rev", kind: kindString, optional: true},
+	}},
+	{name: "moves.json", id: "_id", width: 5, decode: decodeAs[MoveRecord], fields: []field{
+		{name: "type_name", kind: kindString},
+		{name: "identifier", kind: kindString},
+		{name: "power", kind: kindNumber | kindEmpty},
+		{name: "pp", kind: kindNumber | kindEmpty},
+		{name: "accuracy", kind: kindNumber | kindEmpty},
+		{name: "description", kind: kindString},
+		{name: "name", kind: kindString},
+		{name: "priority", kind: kindNumber, optional: true},
+		{name: "damage_class", kind: kindString, optional: true},
+		{name: "_id", kind: kindNumeric},
+		{name: "_rev", kind: kindString, optional: true},
+	}},
+	{name: "exp.json", id: "id", width: 4, species: true, decode: decodeAs[Experience], fields: []field{
+		{name: "id", kind: kindNumeric},
+		{name: "name", kind: kindString},
+		{name: "exp", kind: kindNumeric},
+	}},
+}
+
+// fileIDs are the record IDs of a file that could be read: ids holds each
+// record's ID, or -1 if it has none, and index the first record with each.
+type fileIDs struct {
+	ids   []int
+	index map[int]int
+}
+
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) add(check, file string, index int, id, field, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Check:   check,
+		File:    file,
+		Index:   index,
+		ID:      id,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks the data files in dir against each other: that every
+// record is shaped the way the crawler writes it, that IDs are well formed
+// and unique, that the species, moves and descriptions they refer to exist,
+// that every species has a record in every file, and that the type
+// multipliers agree with the type chart. It returns an error only when a
+// file can't be read at all.
+func Validate(dir string) ([]Problem, error) {
+	v := &validator{}
+	files := make(map[string]*fileIDs)
+	for _, file := range dataFiles {
+		data, err := os.ReadFile(filepath.Join(dir, file.name))
+		if err != nil {
+			return nil, err
+		}
+		ids, ok := v.checkFile(file, data)
+		if ok {
+			files[file.name] = ids
+		}
+	}
+	if len(files) < len(dataFiles) {
+		// Load would fail on the same files, so there is nothing to compare
+		// against.
+		return v.problems, nil
+	}
+
+	d, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	v.checkSpecies(d, files)
+	v.checkDescriptions(d, files)
+	v.checkEvolutions(d, files["evolution.json"])
+	v.checkMoves(d, files["monsterMoves.json"], files["moves.json"])
+	v.checkTypeChart(d, files["MonsterType.json"])
+	return v.problems, nil
+}
+
+// checkFile checks the shape of every record of a file and its IDs. It
+// reports false if Load couldn't read the file.
+func (v *validator) checkFile(file dataFile, data []byte) (*fileIDs, bool) {
+	var records []json.RawMessage
+	if err := json.Unmarshal(data, &records); err != nil {
+		v.add(CheckSchema, file.name, -1, "", "", "not a JSON array: %v", err)
+		return nil, false
+	}
+
+	ids := &fileIDs{ids: make([]int, len(records)), index: make(map[int]int)}
+	for i, raw := range records {
+		ids.ids[i] = -1
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &record); err != nil || record == nil {
+			v.add(CheckSchema, file.name, i, "", "", "want an object, got %s", kindName(raw))
+			continue
+		}
+		v.checkFields(file.name, i, "", record, file.fields)
+
+		value, ok := record[file.id]
+		if !ok {
+			continue
+		}
+		id, ok := v.checkID(file, i, value)
+		if !ok {
+			continue
+		}
+		ids.ids[i] = id
+		if first, seen := ids.index[id]; seen {
+			v.add(CheckDuplicate, file.name, i, idText(value), file.id, "repeats the ID of record %d", first)
+			continue
+		}
+		ids.index[id] = i
+	}
+
+	if err := file.decode(data); err != nil {
+		v.add(CheckSchema, file.name, -1, "", "", "can't be loaded: %v", err)
+		return nil, false
+	}
+	return ids, true
+}
+
+// checkFields checks that a record has the fields it should, holding the
+// kinds of value they should, and nothing else.
+func (v *validator) checkFields(file string, index int, path string, record map[string]json.RawMessage, fields []field) {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.name] = true
+		name := path + f.name
+		value, ok := record[f.name]
+		if !ok {
+			if !f.optional {
+				v.add(CheckSchema, file, index, "", name, "missing")
+			}
+			continue
+		}
+		kinds := valueKinds(value)
+		if kinds&f.kind == 0 {
+			v.add(CheckSchema, file, index, "", name, "want %s, got %s", describeKinds(f.kind), kindName(value))
+			continue
+		}
+		if f.items == nil || kinds&kindArray == 0 {
+			continue
+		}
+		var items []json.RawMessage
+		json.Unmarshal(value, &items)
+		for j, item := range items {
+			itemPath := fmt.Sprintf("%s[%d]", name, j)
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(item, &object); err != nil || object == nil {
+				v.add(CheckSchema, file, index, "", itemPath, "want an object, got %s", kindName(item))
+				continue
+			}
+			v.checkFields(file, index, itemPath+".", object, f.items)
+		}
+	}
+
+	var unknown []string
+	for name := range record {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		v.add(CheckSchema, file, index, "", path+name, "unexpected field")
+	}
+}
+
+// checkID reads the ID of a record, which must be a number zero-padded to
+// the width of the file.
+func (v *validator) checkID(file dataFile, index int, value json.RawMessage) (int, bool) {
+	var number int
+	if err := json.Unmarshal(value, &number); err == nil {
+		return number, true
+	}
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(text)
+	if err != nil || id < 0 {
+		v.add(CheckID, file.name, index, text, file.id, "not a number")
+		return 0, false
+	}
+	if want := fmt.Sprintf("%0*d", file.width, id); text != want {
+		v.add(CheckID, file.name, index, text, file.id, "want %q", want)
+	}
+	return id, true
+}
+
+// checkSpecies checks that every record of a file keyed by national ID is
+// for a species in baseInfo.json, that every species has a record in each of
+// them, and that baseInfo.json names each species once.
+func (v *validator) checkSpecies(d *Dataset, files map[string]*fileIDs) {
+	const base = "baseInfo.json"
+	names := make(map[string]int)
+	for i, pokemon := range d.Pokemons {
+		if id, err := strconv.Atoi(pokemon.ID); err == nil && id != pokemon.NationalID {
+			v.add(CheckID, base, i, pokemon.ID, "national_id", "national ID %d disagrees with the _id", pokemon.NationalID)
+		}
+		key := strings.ToLower(pokemon.Name)
+		if first, seen := names[key]; seen {
+			v.add(CheckDuplicate, base, i, pokemon.ID, "name", "%q is also the name of record %d", pokemon.Name, first)
+		} else {
+			names[key] = i
+		}
+	}
+
+	species := files[base]
+	for _, file := range dataFiles {
+		if !file.species || file.name == base {
+			continue
+		}
+		ids := files[file.name]
+		for i, id := range ids.ids {
+			if _, ok := d.Pokemon(id); id >= 0 && ids.index[id] == i && !ok {
+				v.add(CheckDangling, file.name, i, fmt.Sprintf("%0*d", file.width, id), file.id, "no species with national ID %d in %s", id, base)
+			}
+		}
+		for i, id := range species.ids {
+			if _, ok := ids.index[id]; id >= 0 && species.index[id] == i && !ok {
+				v.add(CheckMissing, file.name, -1, fmt.Sprintf("%0*d", file.width, id), "", "no record for %s (#%d)", d.Pokemons[i].Name, id)
+			}
+		}
+	}
+
+	exps := files["exp.json"]
+	for i, id := range exps.ids {
+		if id < 0 || exps.index[id] != i {
+			continue
+		}
+		exp := d.Experience[id]
+		if pokemon, ok := d.Pokemon(id); ok && !sameSpecies(exp.Name, pokemon.Name) {
+			v.add(CheckID, "exp.json", i, exp.ID, "name", "%q is called %q in %s", exp.Name, pokemon.Name, base)
+		}
+	}
+}
+
+// checkDescriptions checks that every description a species links to is in
+// MonsterDescription.json, whose IDs are the numbers at the end of the links,
+// that the links are named for the species, and that each species has a
+// description of its own or, failing that, of its evolution line.
+func (v *validator) checkDescriptions(d *Dataset, files map[string]*fileIDs) {
+	descriptions := files["MonsterDescription.json"]
+	for i, pokemon := range d.Pokemons {
+		for j, ref := range pokemon.Descriptions {
+			if species, _, _ := strings.Cut(ref.Name, "_"); simplifyName(species) != simplifyName(pokemon.Name) {
+				v.add(CheckID, "baseInfo.json", i, pokemon.ID, fmt.Sprintf("descriptions[%d].name", j), "%q is named for another species", ref.Name)
+			}
+			field := fmt.Sprintf("descriptions[%d].resource_uri", j)
+			id, ok := ref.ResourceID()
+			if !ok {
+				v.add(CheckID, "baseInfo.json", i, pokemon.ID, field, "%q doesn't end in an ID", ref.ResourceURI)
+				continue
+			}
+			if _, ok := descriptions.index[id]; !ok {
+				v.add(CheckDangling, "baseInfo.json", i, pokemon.ID, field, "no description %07d in MonsterDescription.json for %s", id, ref.Name)
+			}
+		}
+		if len(d.SpeciesDescriptions(pokemon.NationalID)) == 0 {
+			v.add(CheckMissing, "baseInfo.json", i, pokemon.ID, "descriptions", "%s has no description of its own or its evolution line's", pokemon.Name)
+		}
+	}
+}
+
+// checkEvolutions checks that every species an evolution leads from or to
+// exists and is called what baseInfo.json calls it.
+func (v *validator) checkEvolutions(d *Dataset, evolutions *fileIDs) {
+	for _, pokemon := range d.Pokemons {
+		evolution, ok := d.Evolutions[pokemon.NationalID]
+		if !ok {
+			continue
+		}
+		i := evolutions.index[pokemon.NationalID]
+		for _, side := range []struct {
+			name    string
+			details []EvolutionDetail
+		}{{"from", evolution.From}, {"to", evolution.To}} {
+			for j, detail := range side.details {
+				field := fmt.Sprintf("%s[%d].nationalId", side.name, j)
+				other, ok := d.Pokemon(detail.NationalID)
+				switch {
+				case !ok:
+					v.add(CheckDangling, "evolution.json", i, evolution.ID, field, "no species with national ID %d (%s) in baseInfo.json", detail.NationalID, detail.Name)
+				case !sameSpecies(detail.Name, other.Name):
+					v.add(CheckID, "evolution.json", i, evolution.ID, field, "%q is called %q in baseInfo.json", detail.Name, other.Name)
+				}
+			}
+		}
+	}
+}
+
+// checkMoves checks that every move a species learns is in moves.json and
+// that no two moves share an identifier.
+func (v *validator) checkMoves(d *Dataset, learnsets, moves *fileIDs) {
+	for _, pokemon := range d.Pokemons {
+		learnset, ok := d.MonsterMoves[pokemon.NationalID]
+		if !ok {
+			continue
+		}
+		i := learnsets.index[pokemon.NationalID]
+		for j, move := range learnset.Moves {
+			if _, ok := d.Moves[move.ID]; !ok {
+				v.add(CheckDangling, "monsterMoves.json", i, learnset.ID, fmt.Sprintf("moves[%d].id", j), "no move %d in moves.json", move.ID)
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(d.Moves))
+	for id := range d.Moves {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	identifiers := make(map[string]int)
+	for _, id := range ids {
+		move := d.Moves[id]
+		if first, seen := identifiers[move.Identifier]; seen {
+			v.add(CheckDuplicate, "moves.json", moves.index[id], fmt.Sprintf("%05d", id), "identifier", "%q is also the identifier of move %d", move.Identifier, first)
+			continue
+		}
+		identifiers[move.Identifier] = id
+	}
+}
+
+// checkTypeChart reports the species whose multipliers in MonsterType.json
+// disagree with the standard type chart, after saying whether the file
+// contradicts itself.
+func (v *validator) checkTypeChart(d *Dataset, types *fileIDs) {
+	const file = "MonsterType.json"
+	if _, mismatches := d.DeriveTypeChart(); len(mismatches) > 0 {
+		v.add(CheckTypeChart, file, -1, "", "", "%d multipliers contradict the chart the single-type species imply", len(mismatches))
+	}
+	for _, pokemon := range d.Pokemons {
+		mult, ok := d.Types[pokemon.NationalID]
+		if !ok {
+			continue
+		}
+		i := types.index[pokemon.NationalID]
+		var wrong []string
+		for _, attackType := range sortedTypes(d.Chart) {
+			recorded := d.recordedMultiplier(pokemon.NationalID, attackType)
+			if expected := d.Chart.Effectiveness(attackType, pokemon.TypeNames()...); recorded != expected {
+				wrong = append(wrong, fmt.Sprintf("%s %gx (want %gx)", attackType, recorded, expected))
+			}
+		}
+		if len(wrong) > 0 {
+			v.add(CheckTypeChart, file, i, strconv.Itoa(mult.ID), "monster_types", "%s (%s): %s", pokemon.Name, strings.Join(pokemon.TypeNames(), "/"), strings.Join(wrong, ", "))
+		}
+	}
+}
+
+// sameSpecies reports whether name names the same species as formName, the
+// way baseInfo.json spells it: "Mr. Mime" is "Mr-mime", "Nidoran♀" is
+// "Nidoran-f" and "Deoxys" is "Deoxys-normal".
+func sameSpecies(name, formName string) bool {
+	name = simplifyName(name)
+	return name != "" && strings.HasPrefix(simplifyName(formName), name)
+}
+
+func sortedTypes(chart TypeChart) []string {
+	types := make([]string, 0, len(chart))
+	for attackType := range chart {
+		types = append(types, attackType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// valueKinds returns every kind a JSON value counts as.
+func valueKinds(value json.RawMessage) int {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return 0
+	}
+	switch v := v.(type) {
+	case string:
+		kinds := kindString
+		if v == "" {
+			kinds |= kindEmpty
+		} else if _, err := strconv.ParseFloat(v, 64); err == nil {
+			kinds |= kindNumeric
+		}
+		return kinds
+	case float64:
+		return kindNumber
+	case []interface{}:
+		return kindArray
+	case map[string]interface{}:
+		return kindObject
+	case nil:
+		return kindNull
+	}
+	return 0
+}
+
+// kindName describes the JSON value for a message.
+func kindName(value json.RawMessage) string {
+	kinds := valueKinds(value)
+	switch {
+	case kinds&kindString != 0:
+		return strconv.Quote(idText(value))
+	case kinds == 0:
+		return "a boolean"
+	}
+	return describeKinds(kinds)
+}
+
+func describeKinds(kinds int) string {
+	var names []string
+	for i, name := range kindNames {
+		if kinds&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// idText returns a JSON string's text, or the JSON itself for anything else.
+func idText(value json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return string(value)
+	}
+	return text
+}
@@ -0,0 +1,380 @@
+package pokedata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type record = map[string]interface{}
+
+// validFiles is a data set Validate finds nothing wrong with: Bulbasaur and
+// Ivysaur, which evolve into one another, with Ekans and Tangela to give
+// poison and grass a single-type species each.
+func validFiles() map[string][]record {
+	species := []struct {
+		id          int
+		name        string
+		types       []string
+		description int
+	}{
+		{1, "Bulbasaur", []string{"grass", "poison"}, 15},
+		{2, "Ivysaur", []string{"grass", "poison"}, 29},
+		{23, "Ekans", []string{"poison"}, 340},
+		{114, "Tangela", []string{"grass"}, 1700},
+	}
+	chart := StandardTypeChart()
+
+	files := make(map[string][]record)
+	for _, s := range species {
+		var types []interface{}
+		for _, t := range s.types {
+			types = append(types, record{"name": t, "resource_uri": "/api/v1/type/" + t + "/"})
+		}
+		files["baseInfo.json"] = append(files["baseInfo.json"], record{
+			"descriptions":      []interface{}{record{"name": fmt.Sprintf("%s_gen_5", s.name), "resource_uri": fmt.Sprintf("/api/v1/description/%d/", s.description)}},
+			"types":             types,
+			"abilities":         []interface{}{record{"name": "overgrow"}},
+			"attack":            49,
+			"defense":           49,
+			"speed":             45,
+			"sp_atk":            65,
+			"sp_def":            65,
+			"hp":                45,
+			"weight":            "69",
+			"height":            "7",
+			"national_id":       s.id,
+			"male_female_ratio": "87.5/12.5",
+			"catch_rate":        45,
+			"_id":               fmt.Sprint(s.id),
+			"name":              s.name,
+		})
+		files["stats.json"] = append(files["stats.json"], record{
+			"specialAttackEV": 1, "hpEV": 0, "hatchSteps": 5100, "defenseEV": 0, "attackEV": 0,
+			"specialDefenseEV": 0, "speedEV": 0, "genderRatio": 87.5, "species": "Seed Pokémon",
+			"japaneseName": "", "Guranburu": "", "eggGroups": "]Monster, Grass",
+			"_id": fmt.Sprintf("%05d", s.id),
+		})
+		files["MonsterDescription.json"] = append(files["MonsterDescription.json"], record{
+			"description": "A strange seed was planted on its back at birth.",
+			"_id":         fmt.Sprintf("%07d", s.description),
+		})
+
+		var multipliers []interface{}
+		for _, attackType := range sortedTypes(chart) {
+			if value := chart.Effectiveness(attackType, s.types...); value != 1 {
+				multipliers = append(multipliers, record{"type": attackType, "multiplier": fmt.Sprintf("%gx", value)})
+			}
+		}
+		files["MonsterType.json"] = append(files["MonsterType.json"], record{"id": s.id, "monster_types": multipliers})
+
+		files["monsterMoves.json"] = append(files["monsterMoves.json"], record{
+			"moves": []interface{}{record{"learn_type": "level up", "level": 1, "id": 33}},
+			"_id":   fmt.Sprintf("%05d", s.id),
+		})
+		files["exp.json"] = append(files["exp.json"], record{"id": fmt.Sprintf("%04d", s.id), "name": s.name, "exp": "64"})
+
+		evolution := record{"from": nil, "to": nil, "_id": fmt.Sprintf("%05d", s.id)}
+		switch s.id {
+		case 1:
+			evolution["to"] = []interface{}{record{"nationalId": 2, "name": "Ivysaur", "method": "level_up", "level": 16}}
+		case 2:
+			evolution["from"] = []interface{}{record{"nationalId": 1, "name": "Bulbasaur", "method": "level_up", "level": 16}}
+		}
+		files["evolution.json"] = append(files["evolution.json"], evolution)
+	}
+	files["moves.json"] = []record{{
+		"type_name": "normal", "identifier": "tackle", "power": 50, "pp": 35, "accuracy": 100,
+		"description": "Inflicts regular damage.", "name": "Tackle", "_id": "00033",
+	}}
+	return files
+}
+
+func writeFiles(t *testing.T, files map[string][]record) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, records := range files {
+		data, err := json.Marshal(records)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestValidateValid(t *testing.T) {
+	problems, err := Validate(writeFiles(t, validFiles()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		t.Errorf("unexpected problem: %s", problem)
+	}
+}
+
+func TestValidateMissingFile(t *testing.T) {
+	dir := writeFiles(t, validFiles())
+	os.Remove(filepath.Join(dir, "exp.json"))
+	if _, err := Validate(dir); err == nil {
+		t.Error("Validate without exp.json succeeded, want an error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// want lists each problem as "check file[index] id field".
+	tests := []struct {
+		name   string
+		change func(files map[string][]record)
+		want   []string
+	}{
+		{
+			"missing field",
+			func(f map[string][]record) { delete(f["baseInfo.json"][0], "hp") },
+			[]string{"schema baseInfo.json[0] hp"},
+		},
+		{
+			"wrong kind",
+			func(f map[string][]record) { f["moves.json"][0]["power"] = "fifty" },
+			[]string{"schema moves.json[0] power"},
+		},
+		{
+			"wrong kind in an array",
+			func(f map[string][]record) {
+				f["monsterMoves.json"][1]["moves"] = []interface{}{record{"learn_type": "level up", "level": "1", "id": 33}}
+			},
+			// Load can't read the file either, so nothing else is checked
+			// against it.
+			[]string{"schema monsterMoves.json[1] moves[0].level", "schema monsterMoves.json[-1]"},
+		},
+		{
+			"unexpected field",
+			func(f map[string][]record) { f["exp.json"][2]["exp_yield"] = 64 },
+			[]string{"schema exp.json[2] exp_yield"},
+		},
+		{
+			"zero padding",
+			func(f map[string][]record) { f["stats.json"][1]["_id"] = "2" },
+			[]string{"id stats.json[1] 2 _id"},
+		},
+		{
+			"national ID disagrees with _id",
+			func(f map[string][]record) { f["baseInfo.json"][3]["_id"] = "115" },
+			[]string{
+				"id baseInfo.json[3] 115 national_id",
+				// The other files are still keyed by the national ID.
+				"missing stats.json[-1] 00115",
+				"missing evolution.json[-1] 00115",
+				"missing MonsterType.json[-1] 115",
+				"missing monsterMoves.json[-1] 00115",
+				"missing exp.json[-1] 0115",
+			},
+		},
+		{
+			"experience for another species",
+			func(f map[string][]record) { f["exp.json"][1]["name"] = "Venusaur" },
+			[]string{"id exp.json[1] 0002 name"},
+		},
+		{
+			"evolution misnamed",
+			func(f map[string][]record) {
+				f["evolution.json"][0]["to"] = []interface{}{record{"nationalId": 2, "name": "Venusaur", "method": "level_up", "level": 16}}
+			},
+			[]string{"id evolution.json[0] 00001 to[0].nationalId"},
+		},
+		{
+			"duplicate ID",
+			func(f map[string][]record) {
+				tackle := f["moves.json"][0]
+				f["moves.json"] = append(f["moves.json"], record{
+					"type_name": "normal", "identifier": "pound", "power": 40, "pp": 35, "accuracy": 100,
+					"description": "Inflicts regular damage.", "name": "Pound", "_id": tackle["_id"],
+				})
+			},
+			[]string{"duplicate moves.json[1] 00033 _id"},
+		},
+		{
+			"duplicate name",
+			func(f map[string][]record) { f["baseInfo.json"][1]["name"] = "bulbasaur" },
+			[]string{
+				"duplicate baseInfo.json[1] 2 name",
+				"id exp.json[1] 0002 name",
+				"id evolution.json[0] 00001 to[0].nationalId",
+				// Its description is still named for Ivysaur.
+				"id baseInfo.json[1] 2 descriptions[0].name",
+				"missing baseInfo.json[1] 2 descriptions",
+			},
+		},
+		{
+			"duplicate move identifier",
+			func(f map[string][]record) {
+				f["moves.json"] = append(f["moves.json"], record{
+					"type_name": "normal", "identifier": "tackle", "power": 40, "pp": 35, "accuracy": 100,
+					"description": "Inflicts regular damage.", "name": "Tackle", "_id": "00034",
+				})
+			},
+			[]string{"duplicate moves.json[1] 00034 identifier"},
+		},
+		{
+			"unknown move",
+			func(f map[string][]record) {
+				f["monsterMoves.json"][0]["moves"] = []interface{}{record{"learn_type": "level up", "level": 1, "id": 99}}
+			},
+			[]string{"dangling monsterMoves.json[0] 00001 moves[0].id"},
+		},
+		{
+			"unknown evolution",
+			func(f map[string][]record) {
+				f["evolution.json"][1]["to"] = []interface{}{record{"nationalId": 3, "name": "Venusaur", "method": "level_up", "level": 32}}
+			},
+			[]string{"dangling evolution.json[1] 00002 to[0].nationalId"},
+		},
+		{
+			"unknown description",
+			func(f map[string][]record) { f["MonsterDescription.json"] = f["MonsterDescription.json"][1:] },
+			[]string{
+				"dangling baseInfo.json[0] 1 descriptions[0].resource_uri",
+				"missing baseInfo.json[0] 1 descriptions",
+			},
+		},
+		{
+			"description of its evolution line only",
+			func(f map[string][]record) {
+				f["baseInfo.json"][1]["descriptions"] = []interface{}{
+					record{"name": "Bulbasaur_gen_5", "resource_uri": "/api/v1/description/15/"},
+					record{"name": "Ivysaur_gen_5", "resource_uri": "/api/v1/description/30/"},
+				}
+			},
+			// Ivysaur falls back to Bulbasaur's description, so it isn't
+			// missing one.
+			[]string{
+				"id baseInfo.json[1] 2 descriptions[0].name",
+				"dangling baseInfo.json[1] 2 descriptions[1].resource_uri",
+			},
+		},
+		{
+			"description of another species",
+			func(f map[string][]record) {
+				f["baseInfo.json"][0]["descriptions"] = append(f["baseInfo.json"][0]["descriptions"].([]interface{}),
+					record{"name": "Ivysaur_gen_5", "resource_uri": "/api/v1/description/29/"})
+				f["baseInfo.json"][2]["descriptions"] = []interface{}{record{"name": "Arbok_gen_5", "resource_uri": "/api/v1/description/340/"}}
+			},
+			[]string{
+				"id baseInfo.json[0] 1 descriptions[1].name",
+				"id baseInfo.json[2] 23 descriptions[0].name",
+				"missing baseInfo.json[2] 23 descriptions",
+			},
+		},
+		{
+			"record for an unknown species",
+			func(f map[string][]record) { f["stats.json"][3]["_id"] = "00151" },
+			[]string{
+				"dangling stats.json[3] 00151 _id",
+				"missing stats.json[-1] 00114",
+			},
+		},
+		{
+			"missing record",
+			func(f map[string][]record) { f["exp.json"] = f["exp.json"][:3] },
+			[]string{"missing exp.json[-1] 0114"},
+		},
+		{
+			"no descriptions",
+			func(f map[string][]record) { f["baseInfo.json"][2]["descriptions"] = []interface{}{} },
+			[]string{"missing baseInfo.json[2] 23 descriptions"},
+		},
+		{
+			"multiplier off the chart",
+			func(f map[string][]record) {
+				// Grass is 0.25x against grass/poison, not neutral.
+				var multipliers []interface{}
+				for _, m := range f["MonsterType.json"][1]["monster_types"].([]interface{}) {
+					if m.(record)["type"] != "grass" {
+						multipliers = append(multipliers, m)
+					}
+				}
+				f["MonsterType.json"][1]["monster_types"] = multipliers
+			},
+			// Ivysaur now also disagrees with Bulbasaur, so the file
+			// contradicts itself.
+			[]string{"type_chart MonsterType.json[-1]", "type_chart MonsterType.json[1] 2 monster_types"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := validFiles()
+			tt.change(files)
+			problems, err := Validate(writeFiles(t, files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range problems {
+				got = append(got, strings.Join(strings.Fields(fmt.Sprintf("%s %s[%d] %s %s", p.Check, p.File, p.Index, p.ID, p.Field)), " "))
+			}
+			if !sameStrings(got, tt.want) {
+				t.Errorf("problems:\n%s\ngot %q\nwant %q", problemList(problems), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUnreadableFile(t *testing.T) {
+	dir := writeFiles(t, validFiles())
+	if err := os.WriteFile(filepath.Join(dir, "moves.json"), []byte(`{"_id": "00033"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is compared against a file Load can't read.
+	problems, err := Validate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Check != CheckSchema || problems[0].File != "moves.json" || problems[0].Index != -1 {
+		t.Errorf("problems:\n%s\nwant one schema problem with moves.json", problemList(problems))
+	}
+}
+
+func sameStrings(got, want []string) bool {
+	counts := make(map[string]int)
+	for _, s := range got {
+		counts[s]++
+	}
+	for _, s := range want {
+		counts[s]--
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func problemList(problems []Problem) string {
+	var list string
+	for _, p := range problems {
+		list += "\t" + p.String() + "\n"
+	}
+	return list
+}
+
+func TestProblemString(t *testing.T) {
+	tests := []struct {
+		problem Problem
+		want    string
+	}{
+		{Problem{Check: CheckSchema, File: "moves.json", Index: -1, Message: "not a JSON array"}, "schema: moves.json: not a JSON array"},
+		{Problem{Check: CheckID, File: "stats.json", Index: 1, ID: "2", Field: "_id", Message: `want "00002"`}, `id: stats.json[1] 2 _id: want "00002"`},
+	}
+	for _, tt := range tests {
+		if got := tt.problem.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"Pokemon/pokedata"
+)
+
+var allSections = []string{"info", "stats", "description", "evolutions", "weaknesses", "moves"}
+
+// show prints the chosen sections of a Pokémon's Pokédex entry. Moves are left
+// out unless asked for, since most species learn dozens of them.
+func show(data *pokedata.Dataset, args []string) error {
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	sections := flags.String("sections", "info,stats,description,evolutions,weaknesses",
+		"comma-separated sections to show: "+strings.Join(allSections, ", ")+", or all")
+	flags.Parse(args)
+
+	info, err := lookup(data, flags.Args())
+	if err != nil {
+		return err
+	}
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(*sections, ",") {
+		name = strings.TrimSpace(name)
+		if name == "all" {
+			for _, s := range allSections {
+				selected[s] = true
+			}
+			continue
+		}
+		if !contains(allSections, name) {
+			return fmt.Errorf("Unknown section %q. Sections are %s.", name, strings.Join(allSections, ", "))
+		}
+		selected[name] = true
+	}
+
+	pokemon := info.Pokemon
+	fmt.Printf("#%03d %s\n", pokemon.NationalID, pokemon.Name)
+	if selected["info"] {
+		section("Info")
+		t := newTable("Field", "Value")
+		t.add("Types", names(pokemon.Types))
+		t.add("Abilities", names(pokemon.Abilities))
+		t.add("Species", info.Additional.Species)
+		t.add("Egg groups", strings.Join(info.Additional.EggGroupList(), ", "))
+		t.add("Height", pokemon.Height)
+		t.add("Weight", pokemon.Weight)
+		t.add("Male/female", pokemon.MaleFemaleRatio)
+		t.add("Base experience", info.Experience.Exp)
+		t.print(0, 0)
+	}
+	if selected["stats"] {
+		section("Base stats")
+		t := newTable("Stat", "Value", "EV yield")
+		t.add("HP", strconv.Itoa(pokemon.HP), strconv.Itoa(info.Additional.HPEV))
+		t.add("Attack", strconv.Itoa(pokemon.Attack), strconv.Itoa(info.Additional.AttackEV))
+		t.add("Defense", strconv.Itoa(pokemon.Defense), strconv.Itoa(info.Additional.DefenseEV))
+		t.add("Sp. Atk", strconv.Itoa(pokemon.SpAtk), strconv.Itoa(info.Additional.SpecialAttackEV))
+		t.add("Sp. Def", strconv.Itoa(pokemon.SpDef), strconv.Itoa(info.Additional.SpecialDefenseEV))
+		t.add("Speed", strconv.Itoa(pokemon.Speed), strconv.Itoa(info.Additional.SpeedEV))
+		total, _ := pokemon.Stat("total")
+		t.add("Total", strconv.Itoa(total), "")
+		t.print(0, 0)
+	}
+	if selected["description"] {
+		section("Description")
+		printDescriptions(info.Descriptions)
+	}
+	if selected["evolutions"] {
+		section("Evolutions")
+		printEvolutions(data, pokemon.NationalID)
+	}
+	if selected["weaknesses"] {
+		section("Type matchups")
+		printWeaknesses(data, info)
+	}
+	if selected["moves"] {
+		section("Moves")
+		printMoves(info, "", 0, 0)
+	}
+	return nil
+}
+
+func moves(data *pokedata.Dataset, args []string) error {
+	flags := flag.NewFlagSet("moves", flag.ExitOnError)
+	method := flags.String("method", "", "only moves learned this way: level up, machine, tutor, egg move or other")
+	page := flags.Int("page", 1, "page to show")
+	perPage := flags.Int("per-page", 20, "moves per page, 0 for all")
+	flags.Parse(args)
+	if err := checkPaging(*page, *perPage); err != nil {
+		return err
+	}
+
+	info, err := lookup(data, flags.Args())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("#%03d %s\n", info.Pokemon.NationalID, info.Pokemon.Name)
+	printMoves(info, *method, *page, *perPage)
+	return nil
+}
+
+func evolutions(data *pokedata.Dataset, args []string) error {
+	info, err := lookup(data, args)
+	if err != nil {
+		return err
+	}
+	printEvolutions(data, info.Pokemon.NationalID)
+	return nil
+}
+
+func weaknesses(data *pokedata.Dataset, args []string) error {
+	info, err := lookup(data, args)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("#%03d %s (%s)\n", info.Pokemon.NationalID, info.Pokemon.Name, names(info.Pokemon.Types))
+	printWeaknesses(data, info)
+	return nil
+}
+
+// compare prints a side-by-side report on several Pokémon: their stats and
+// how they differ from the first one, their moves, how their attacks fare
+// against each other and who moves first.
+func compare(data *pokedata.Dataset, args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		return fmt.Errorf("Name at least two Pokémon to compare.")
+	}
+	var ids []int
+	for _, arg := range flags.Args() {
+		info, err := lookup(data, []string{arg})
+		if err != nil {
+			return err
+		}
+		ids = append(ids, info.Pokemon.NationalID)
+	}
+	report := data.Compare(ids...)
+
+	if *asJSON {
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	section("Base stats")
+	headers := []string{"Stat"}
+	for _, pokemon := range report.Pokemon {
+		headers = append(headers, pokemon.Name)
+	}
+	t := newTable(headers...)
+	rows := []struct {
+		label string
+		value func(pokedata.BaseStats) int
+	}{
+		{"HP", func(s pokedata.BaseStats) int { return s.HP }},
+		{"Attack", func(s pokedata.BaseStats) int { return s.Attack }},
+		{"Defense", func(s pokedata.BaseStats) int { return s.Defense }},
+		{"Sp. Atk", func(s pokedata.BaseStats) int { return s.SpAtk }},
+		{"Sp. Def", func(s pokedata.BaseStats) int { return s.SpDef }},
+		{"Speed", func(s pokedata.BaseStats) int { return s.Speed }},
+		{"Total", func(s pokedata.BaseStats) int { return s.Total }},
+	}
+	for _, row := range rows {
+		cells := []string{row.label}
+		for i, pokemon := range report.Pokemon {
+			cell := strconv.Itoa(row.value(pokemon.Stats))
+			if i > 0 {
+				cell += fmt.Sprintf(" (%+d)", row.value(pokemon.Deltas))
+			}
+			cells = append(cells, cell)
+		}
+		t.add(cells...)
+	}
+	t.print(0, 0)
+
+	section("Type matchups")
+	t = newTable("Attacker", "Move type", "Defender", "Damage")
+	for _, matchup := range report.Matchups {
+		t.add(matchup.Attacker, matchup.AttackType, matchup.Defender,
+			strconv.FormatFloat(matchup.Multiplier, 'g', -1, 64)+"x")
+	}
+	t.print(0, 0)
+
+	section("Speed")
+	for _, outspeed := range report.Outspeeds {
+		if outspeed.Tie {
+			fmt.Printf("%s and %s tie on speed\n", outspeed.Faster, outspeed.Slower)
+		} else {
+			fmt.Printf("%s outspeeds %s\n", outspeed.Faster, outspeed.Slower)
+		}
+	}
+
+	section("Moves")
+	fmt.Printf("Shared by all (%d): %s\n", len(report.SharedMoves), strings.Join(report.SharedMoves, ", "))
+	for _, pokemon := range report.Pokemon {
+		fmt.Printf("\nOnly %s (%d): %s\n", pokemon.Name, len(pokemon.ExclusiveMoves), strings.Join(pokemon.ExclusiveMoves, ", "))
+	}
+	return nil
+}
+
+func search(data *pokedata.Dataset, args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	page := flags.Int("page", 1, "page to show")
+	perPage := flags.Int("per-page", 20, "Pokémon per page, 0 for all")
+	flags.Parse(args)
+	if err := checkPaging(*page, *perPage); err != nil {
+		return err
+	}
+
+	query, err := pokedata.ParseQuery(strings.Join(flags.Args(), " "))
+	if err != nil {
+		return fmt.Errorf("Invalid search: %w", err)
+	}
+	if query.Limit == 0 && *perPage > 0 {
+		if *page-1 > math.MaxInt / *perPage {
+			return fmt.Errorf("Page %d is too far to show.", *page)
+		}
+		query.Limit = *perPage
+		query.Offset = (*page - 1) * *perPage
+	}
+
+	result := data.Search(query)
+	t := newTable("#", "Name", "Types", "HP", "Atk", "Def", "SpA", "SpD", "Spe", "Total")
+	for _, pokemon := range result.Pokemons {
+		total, _ := pokemon.Stat("total")
+		t.add(strconv.Itoa(pokemon.NationalID), pokemon.Name, names(pokemon.Types),
+			strconv.Itoa(pokemon.HP), strconv.Itoa(pokemon.Attack), strconv.Itoa(pokemon.Defense),
+			strconv.Itoa(pokemon.SpAtk), strconv.Itoa(pokemon.SpDef), strconv.Itoa(pokemon.Speed), strconv.Itoa(total))
+	}
+	t.print(0, 0)
+	if query.Limit > 0 && result.Total > 0 {
+		fmt.Printf("Showing %d-%d of %d matching Pokémon\n", query.Offset+1, query.Offset+len(result.Pokemons), result.Total)
+	} else {
+		fmt.Printf("%d matching Pokémon\n", result.Total)
+	}
+	return nil
+}
+
+// checkPaging rejects the -page and -per-page values no table can show.
+func checkPaging(page, perPage int) error {
+	if page < 1 {
+		return fmt.Errorf("-page must be at least 1, not %d.", page)
+	}
+	if perPage < 0 {
+		return fmt.Errorf("-per-page must be 0 or more, not %d.", perPage)
+	}
+	return nil
+}
+
+// learnOrder puts level-up moves first, the way the games list them.
+var learnOrder = map[string]int{"level up": 0, "machine": 1, "tutor": 2, "egg move": 3, "other": 4}
+
+func printMoves(info pokedata.PokemonInfo, method string, page, perPage int) {
+	learned := append([]pokedata.LearnedMove(nil), info.MonsterMoves.Moves...)
+	sort.SliceStable(learned, func(i, j int) bool {
+		if learned[i].LearnType != learned[j].LearnType {
+			return learnOrder[learned[i].LearnType] < learnOrder[learned[j].LearnType]
+		}
+		return learned[i].Level < learned[j].Level
+	})
+
+	t := newTable("Method", "Level", "Move", "Type", "Class", "Power", "Acc", "PP", "Priority")
+	for _, move := range learned {
+		if method != "" && !strings.EqualFold(move.LearnType, method) {
+			continue
+		}
+		level := "-"
+		if move.LearnType == "level up" {
+			level = strconv.Itoa(move.Level)
+		}
+		details := move.Details
+		if details == nil {
+			details = &pokedata.Move{Name: fmt.Sprintf("#%d", move.ID)}
+		}
+		t.add(move.LearnType, level, details.Name, details.TypeName, string(details.DamageClass),
+			moveValue(details.Power), moveValue(details.Accuracy), moveValue(details.PP), strconv.Itoa(details.Priority))
+	}
+	t.print(page, perPage)
+}
+
+func printEvolutions(data *pokedata.Dataset, nationalID int) {
+	line := data.EvolutionLine(nationalID)
+	if len(line) == 1 {
+		fmt.Println("Does not evolve.")
+		return
+	}
+	t := newTable("Stage", "#", "Name", "From", "How")
+	for _, step := range line {
+		how := ""
+		switch {
+		case step.Method == "level_up" && step.Level > 0:
+			how = fmt.Sprintf("level %d", step.Level)
+		case step.Method != "":
+			how = strings.ReplaceAll(step.Method, "_", " ")
+		}
+		name := step.Name
+		if step.NationalID == nationalID {
+			name += " *"
+		}
+		t.add(strconv.Itoa(step.Stage), strconv.Itoa(step.NationalID), name, step.From, how)
+	}
+	t.print(0, 0)
+}
+
+// printDescriptions prints the flavor texts, naming the game version of
+// each when there is more than one.
+func printDescriptions(descriptions []pokedata.Description) {
+	if len(descriptions) == 0 {
+		fmt.Println("No description.")
+		return
+	}
+	for _, desc := range descriptions {
+		if len(descriptions) > 1 && desc.Version != "" {
+			fmt.Printf("%s: ", strings.ToUpper(desc.Version[:1])+desc.Version[1:])
+		}
+		if desc.Species != "" {
+			fmt.Printf("(%s's entry) ", desc.Species)
+		}
+		fmt.Println(desc.Description)
+	}
+}
+
+// printWeaknesses groups the attacking types by how much damage they deal.
+func printWeaknesses(data *pokedata.Dataset, info pokedata.PokemonInfo) {
+	groups := make(map[float64][]string)
+	for _, attackType := range data.TypeNames() {
+		value := data.Effectiveness(attackType, info.Pokemon.TypeNames()...)
+		groups[value] = append(groups[value], attackType)
+	}
+	var multipliers []float64
+	for multiplier := range groups {
+		multipliers = append(multipliers, multiplier)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(multipliers)))
+
+	t := newTable("Damage", "Attacking types")
+	for _, multiplier := range multipliers {
+		types := groups[multiplier]
+		sort.Strings(types)
+		t.add(strconv.FormatFloat(multiplier, 'g', -1, 64)+"x", strings.Join(types, ", "))
+	}
+	t.print(0, 0)
+}
+
+// moveValue formats the power, accuracy or PP of a move, or a dash when the
+// move has none.
+func moveValue(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
+
+func names(objects []pokedata.ListMapObject) string {
+	var values []string
+	for _, object := range objects {
+		values = append(values, object.Name)
+	}
+	return strings.Join(values, ", ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
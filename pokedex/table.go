@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// table prints rows as aligned columns under a header.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func newTable(headers ...string) *table {
+	return &table{headers: headers}
+}
+
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// print writes one page of the table. A perPage of 0 prints every row.
+func (t *table) print(page, perPage int) {
+	rows := t.rows
+	pages := 1
+	if perPage > 0 && len(rows) > perPage {
+		pages = (len(rows) + perPage - 1) / perPage
+		if page < 1 {
+			page = 1
+		}
+		if page > pages {
+			page = pages
+		}
+		start := (page - 1) * perPage
+		end := start + perPage
+		if end > len(rows) {
+			end = len(rows)
+		}
+		rows = rows[start:end]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.headers, "\t"))
+	separators := make([]string, len(t.headers))
+	for i, header := range t.headers {
+		separators[i] = strings.Repeat("-", len([]rune(header)))
+	}
+	fmt.Fprintln(w, strings.Join(separators, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	if pages > 1 {
+		fmt.Printf("Page %d of %d (%d rows). Use -page to see more.\n", page, pages, len(t.rows))
+	}
+}
+
+// section prints a heading for one part of the output.
+func section(title string) {
+	fmt.Printf("\n%s\n%s\n", title, strings.Repeat("=", len([]rune(title))))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Pokemon/pokedata"
+)
+
+// team analyses the type coverage of up to six Pokémon. Each argument is a
+// Pokémon, optionally followed by the moves it knows, like
+// "charizard:flamethrower,air-slash".
+func team(data *pokedata.Dataset, args []string) error {
+	flags := flag.NewFlagSet("team", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the analysis as JSON")
+	flags.Parse(args)
+
+	var members []pokedata.TeamMember
+	for _, arg := range flags.Args() {
+		name, moveList, _ := strings.Cut(arg, ":")
+		info, err := lookup(data, []string{name})
+		if err != nil {
+			return err
+		}
+		member := pokedata.TeamMember{NationalID: info.Pokemon.NationalID}
+		for _, move := range strings.Split(moveList, ",") {
+			if move = strings.TrimSpace(move); move == "" {
+				continue
+			}
+			if _, ok := data.MoveByName(move); !ok {
+				message := fmt.Sprintf("Unknown move %q.", move)
+				if suggestions := data.SuggestMoves(move, 3); len(suggestions) > 0 {
+					message += " Did you mean " + strings.Join(suggestions, ", ") + "?"
+				}
+				return fmt.Errorf("%s", message)
+			}
+			member.Moves = append(member.Moves, move)
+		}
+		members = append(members, member)
+	}
+
+	analysis, err := data.AnalyzeTeam(members)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		jsonData, err := json.MarshalIndent(analysis, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	fmt.Println("Team:", strings.Join(analysis.Members, ", "))
+
+	section("Defense")
+	t := newTable("Attack type", "Weak", "Resistant", "Immune")
+	for _, defense := range analysis.Defense {
+		t.add(defense.AttackType, listOrDash(defense.Weak), listOrDash(defense.Resistant), listOrDash(defense.Immune))
+	}
+	t.print(0, 0)
+
+	section("Offensive coverage")
+	t = newTable("Defending type", "Best damage", "With")
+	for _, coverage := range analysis.Coverage {
+		t.add(coverage.DefenseType, strconv.FormatFloat(coverage.Multiplier, 'g', -1, 64)+"x", listOrDash(coverage.AttackTypes))
+	}
+	t.print(0, 0)
+
+	section("Gaps")
+	fmt.Println("Shared weaknesses:", listOrDash(analysis.SharedWeaknesses))
+	fmt.Println("Not hit super effectively:", listOrDash(analysis.Uncovered))
+
+	if len(analysis.Suggestions) > 0 {
+		section("Suggestions")
+		t = newTable("#", "Name", "Resists", "Covers")
+		for _, suggestion := range analysis.Suggestions {
+			t.add(strconv.Itoa(suggestion.NationalID), suggestion.Name, listOrDash(suggestion.Resists), listOrDash(suggestion.Covers))
+		}
+		t.print(0, 0)
+	}
+	return nil
+}
+
+func listOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
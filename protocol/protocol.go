@@ -0,0 +1,225 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Version is bumped whenever a message or payload changes incompatibly.
+const Version = 1
+
+// maxMessageSize bounds a single line so a misbehaving peer can't exhaust memory.
+const maxMessageSize = 1 << 20
+
+// Message types sent by the client.
+const (
+	TypeLogin         = "login"
+	TypeRegister      = "register"
+	TypeAddPokemon    = "add_pokemon"
+	TypeRemovePokemon = "remove_pokemon"
+	TypeChoose        = "choose"
+	TypeReady         = "ready"
+	TypeAction        = "action"
+	TypeEvolve        = "evolve"
+)
+
+// Message types sent by the server.
+const (
+	TypeRoster    = "roster"
+	TypePrompt    = "prompt"
+	TypeEvent     = "event"
+	TypeResult    = "result"
+	TypeEvolution = "evolution"
+	TypeError     = "error"
+)
+
+// Action kinds carried by an Action payload.
+const (
+	ActionMove    = "move"
+	ActionSwitch  = "switch"
+	ActionForfeit = "forfeit"
+)
+
+// ErrMalformed is returned by Receive when a line is not a valid message. The
+// connection is still usable afterwards.
+var ErrMalformed = errors.New("malformed message")
+
+// Message is one line on the wire: a JSON object with a type and its payload.
+type Message struct {
+	Version int             `json:"version"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Login is the payload of both login and register messages. The password may
+// be empty for accounts that don't have one.
+type Login struct {
+	Name     string `json:"name"`
+	Password string `json:"password,omitempty"`
+}
+
+// RosterChange names a Pokémon to add to or remove from the roster. Additions
+// accept a national ID or a species name; removals use the roster ID.
+type RosterChange struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type Choose struct {
+	PokemonIDs []int `json:"pokemon_ids"`
+}
+
+type Action struct {
+	Kind string `json:"kind"`
+	// Move is the 1-based moveset slot, used when MoveName is empty.
+	Move     int    `json:"move,omitempty"`
+	MoveName string `json:"move_name,omitempty"`
+}
+
+// RosterEntry is one Pokémon in a player's roster. ID is its roster ID, which
+// choose and remove messages refer to.
+type RosterEntry struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
+
+type Roster struct {
+	Pokemons []RosterEntry `json:"pokemons"`
+}
+
+type MoveOption struct {
+	Slot  int    `json:"slot"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	PP    int    `json:"pp"`
+	MaxPP int    `json:"max_pp"`
+}
+
+type Prompt struct {
+	Pokemon   string       `json:"pokemon"`
+	Level     int          `json:"level"`
+	HP        int          `json:"hp"`
+	MaxHP     int          `json:"max_hp"`
+	Status    string       `json:"status,omitempty"`
+	Moves     []MoveOption `json:"moves"`
+	CanSwitch bool         `json:"can_switch"`
+}
+
+type Event struct {
+	Text string `json:"text"`
+}
+
+const (
+	OutcomeWin  = "win"
+	OutcomeLose = "lose"
+)
+
+type Result struct {
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason"`
+}
+
+// Evolution asks the player whether a Pokémon that leveled up should evolve.
+// The client answers with an Evolve message within Timeout seconds, or the
+// Pokémon doesn't evolve.
+type Evolution struct {
+	PokemonID int    `json:"pokemon_id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Timeout   int    `json:"timeout,omitempty"`
+}
+
+type Evolve struct {
+	Cancel bool `json:"cancel"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Conn reads and writes newline-delimited messages. Send is safe to call from
+// several goroutines; Receive must only be called from one.
+type Conn struct {
+	rwc     io.ReadWriteCloser
+	scanner *bufio.Scanner
+	mu      sync.Mutex
+}
+
+func NewConn(rwc io.ReadWriteCloser) *Conn {
+	scanner := bufio.NewScanner(rwc)
+	scanner.Buffer(make([]byte, 4096), maxMessageSize)
+	return &Conn{rwc: rwc, scanner: scanner}
+}
+
+// Send writes a message of the given type with payload encoded as JSON.
+func (c *Conn) Send(msgType string, payload interface{}) error {
+	msg := Message{Version: Version, Type: msgType}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = data
+	}
+
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.rwc.Write(line)
+	return err
+}
+
+// Receive reads the next message. Errors wrapping ErrMalformed describe a bad
+// line; any other error means the connection is unusable.
+func (c *Conn) Receive() (Message, error) {
+	var msg Message
+	for c.scanner.Scan() {
+		line := c.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return msg, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if msg.Version != Version {
+			return msg, fmt.Errorf("%w: unsupported protocol version %d, expected %d", ErrMalformed, msg.Version, Version)
+		}
+		if msg.Type == "" {
+			return msg, fmt.Errorf("%w: missing message type", ErrMalformed)
+		}
+		return msg, nil
+	}
+
+	if err := c.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return msg, fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+		}
+		return msg, err
+	}
+	return msg, io.EOF
+}
+
+func (c *Conn) Close() error {
+	return c.rwc.Close()
+}
+
+// Decode unmarshals the message payload into v.
+func (m Message) Decode(v interface{}) error {
+	if len(m.Payload) == 0 {
+		return fmt.Errorf("%w: %s message has no payload", ErrMalformed, m.Type)
+	}
+	if err := json.Unmarshal(m.Payload, v); err != nil {
+		return fmt.Errorf("%w: invalid %s payload: %v", ErrMalformed, m.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
+)
+
+// Battle is a match between two players. Each battle runs in its own
+// goroutine and only touches its own players.
+type Battle struct {
+	ID      int
+	Players [2]*Player
+	store   *PlayerStore
+
+	mu       sync.Mutex
+	finished bool
+}
+
+func newBattle(id int, store *PlayerStore, player1, player2 *Player) *Battle {
+	return &Battle{ID: id, Players: [2]*Player{player1, player2}, store: store}
+}
+
+// Run plays rounds until one side wins or forfeits, saves both rosters, then
+// releases both players. A player with a Pokémon that can evolve is released
+// last, once they have answered the evolution prompts.
+func (b *Battle) Run() {
+	fmt.Printf("Battle %d start: %s vs %s\n", b.ID, b.Players[0].Name, b.Players[1].Name)
+
+	for !b.Finished() {
+		// Both players choose their actions before anything is resolved
+		actions := make([]Action, len(b.Players))
+		var wg sync.WaitGroup
+		for i, player := range b.Players {
+			wg.Add(1)
+			go func(i int, player *Player) {
+				defer wg.Done()
+				actions[i] = readAction(player)
+			}(i, player)
+		}
+		wg.Wait()
+
+		b.resolveRound(actions)
+	}
+	fmt.Printf("Battle %d finished\n", b.ID)
+
+	leveled := b.saveRosters()
+	for _, player := range b.Players {
+		if len(leveled[player]) == 0 {
+			close(player.done)
+		}
+	}
+	for _, player := range b.Players {
+		if len(leveled[player]) > 0 {
+			b.evolveRoster(player, leveled[player])
+			close(player.done)
+		}
+	}
+}
+
+// saveRosters awards what each player's Pokémon earned during the battle and
+// writes the rosters back to the player store. It returns each player's
+// Pokémon that leveled up.
+func (b *Battle) saveRosters() map[*Player][]*Pokemon {
+	leveled := make(map[*Player][]*Pokemon)
+	for _, player := range b.Players {
+		awardEVs(player)
+		if player.won {
+			leveled[player] = awardExperience(player)
+		}
+		b.saveRoster(player)
+	}
+	return leveled
+}
+
+// evolveRoster offers to evolve each of the player's Pokémon that leveled up
+// and saves the roster again if any of them did.
+func (b *Battle) evolveRoster(player *Player, pokemons []*Pokemon) {
+	evolved := false
+	for _, pokemon := range pokemons {
+		if evolve(player, pokemon) {
+			evolved = true
+		}
+	}
+	if evolved {
+		b.saveRoster(player)
+	}
+}
+
+func (b *Battle) saveRoster(player *Player) {
+	if err := b.store.UpdateRoster(player.Name, player.Pokemons); err != nil {
+		fmt.Printf("Battle %d: error saving %s's roster: %s\n", b.ID, player.Name, err)
+	}
+}
+
+// Finished reports whether the battle has ended.
+func (b *Battle) Finished() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.finished
+}
+
+func (b *Battle) opponent(player *Player) *Player {
+	if b.Players[0] == player {
+		return b.Players[1]
+	}
+	return b.Players[0]
+}
+
+func (b *Battle) resolveRound(actions []Action) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	orderActions(actions)
+	for _, action := range actions {
+		// A Pokémon that fainted earlier in the round doesn't get to move
+		if action.Kind == protocol.ActionMove && action.Player.Active[0] != action.Active {
+			continue
+		}
+		if processAction(action, b.opponent(action.Player)) {
+			b.finished = true
+			return
+		}
+	}
+
+	// Burns and poison hurt at the end of the round
+	for _, player := range b.Players {
+		if applyResidualDamage(player, b.opponent(player)) {
+			b.finished = true
+			return
+		}
+	}
+}
+
+// readAction prompts the player until they submit a valid action. A player
+// who disconnects forfeits.
+func readAction(player *Player) Action {
+	err := player.Conn.Send(protocol.TypePrompt, turnPrompt(player))
+	for err == nil {
+		var msg protocol.Message
+		msg, err = player.receive(nil)
+		if err != nil {
+			break
+		}
+		if msg.Type != protocol.TypeAction {
+			player.sendError(fmt.Sprintf("Expected an action, got %s.", msg.Type))
+			continue
+		}
+
+		var submitted protocol.Action
+		if decodeErr := msg.Decode(&submitted); decodeErr != nil {
+			player.sendError(decodeErr.Error())
+			continue
+		}
+		action, actionErr := parseAction(player, submitted)
+		if actionErr != nil {
+			player.sendError(actionErr.Error())
+			continue
+		}
+		player.event("Waiting for opponent...")
+		return action
+	}
+	fmt.Println("Lost connection to", player.Name)
+	return Action{Player: player, Kind: protocol.ActionForfeit, Active: player.Active[0]}
+}
+
+func turnPrompt(player *Player) protocol.Prompt {
+	prompt := protocol.Prompt{
+		HP:        player.Health[0],
+		Status:    string(player.Status[0].Status),
+		CanSwitch: len(player.Active) > 1,
+	}
+	if pokemon := findPokemonByID(player.Active[0], player); pokemon != nil {
+		prompt.Pokemon = pokemon.Name
+		prompt.Level = level(pokemon)
+		prompt.MaxHP = calculateStats(pokemon).HP
+	}
+	for i, slot := range player.Moves[0] {
+		prompt.Moves = append(prompt.Moves, protocol.MoveOption{
+			Slot:  i + 1,
+			Name:  slot.Move.Name,
+			Type:  slot.Move.TypeName,
+			PP:    slot.PP,
+			MaxPP: slot.MaxPP,
+		})
+	}
+	return prompt
+}
+
+func getSpeedOfFirstActivePokemon(player *Player) int {
+	for _, pokemon := range player.Pokemons {
+		if pokemon.RosterID == player.Active[0] {
+			speed := calculateStats(&pokemon).Speed
+			if player.Status[0].Status == StatusParalysis {
+				return speed / 4
+			}
+			return speed
+		}
+	}
+	return 0
+}
+
+// processAction carries out one player's action for the round and reports
+// whether it ended the battle.
+func processAction(action Action, opponentPlayer *Player) bool {
+	currentPlayer := action.Player
+	switch action.Kind {
+	case protocol.ActionMove:
+		return useMove(currentPlayer, opponentPlayer, action.Slot)
+	case protocol.ActionSwitch:
+		// Handle Pokémon switching logic
+		switchPokemon(currentPlayer)
+		currentPlayer.event("You switched Pokémon.")
+		opponentPlayer.event("Opponent switched Pokémon.")
+	case protocol.ActionForfeit:
+		// Handle player forfeiting the match
+		currentPlayer.result(protocol.OutcomeLose, "You forfeited.")
+		opponentPlayer.result(protocol.OutcomeWin, "Opponent forfeited.")
+		return true
+	}
+	return false
+}
+
+// useMove spends PP for the chosen slot and attacks the opponent's active
+// Pokémon, reporting whether the opponent has no Pokémon left.
+func useMove(currentPlayer, opponentPlayer *Player, slot int) bool {
+	canMove, message := checkCanMove(&currentPlayer.Status[0])
+	if message != "" {
+		announce(currentPlayer, opponentPlayer, message)
+	}
+	if !canMove {
+		return false
+	}
+
+	move := dataset.Moves[struggleID]
+	if slot >= 0 {
+		currentPlayer.Moves[0][slot].PP--
+		move = currentPlayer.Moves[0][slot].Move
+	}
+
+	result := calculateDamage(currentPlayer, opponentPlayer, move)
+	if result.Missed {
+		fmt.Printf("%s used %s but missed\n", currentPlayer.Name, move.Name)
+		currentPlayer.event("You used %s but it missed!", move.Name)
+		opponentPlayer.event("Opponent used %s but it missed!", move.Name)
+		return false
+	}
+	if !move.Damaging() {
+		currentPlayer.event("You used %s.", move.Name)
+		opponentPlayer.event("Opponent used %s.", move.Name)
+		inflictStatus(currentPlayer, opponentPlayer, move, true)
+		return false
+	}
+	damage := result.Damage
+	effect := effectivenessMessage(result.Effectiveness)
+	opponentPlayer.Health[0] -= damage
+	fmt.Printf("%s used %s and dealt %d damage to %s's first Pokémon. Remaining HP: %d\n", currentPlayer.Name, move.Name, damage, opponentPlayer.Name, opponentPlayer.Health[0])
+
+	// Notify players
+	currentPlayer.event("You used %s and dealt %d damage.%s Opponent's Pokémon remaining HP: %d", move.Name, damage, effect, opponentPlayer.Health[0])
+	opponentPlayer.event("Opponent used %s and dealt %d damage to your Pokémon.%s Remaining HP: %d", move.Name, damage, effect, opponentPlayer.Health[0])
+
+	// Check if opponent's Pokémon fainted
+	if opponentPlayer.Health[0] <= 0 {
+		return faint(opponentPlayer, currentPlayer)
+	}
+
+	// Fire moves thaw a frozen target
+	if move.TypeName == "fire" && damage > 0 && opponentPlayer.Status[0].Status == StatusFreeze {
+		opponentPlayer.Status[0] = StatusCondition{}
+		announce(opponentPlayer, currentPlayer, "thawed out!")
+	}
+	inflictStatus(currentPlayer, opponentPlayer, move, false)
+	return false
+}
+
+// inflictStatus rolls for the status effect of a move that hit. Moves whose
+// only purpose is the status report when it fails.
+func inflictStatus(currentPlayer, opponentPlayer *Player, move pokedata.Move, statusMove bool) {
+	effect, ok := moveStatusEffect(move)
+	if !ok || rand.Intn(100) >= effect.Chance {
+		return
+	}
+
+	status := effect.Statuses[rand.Intn(len(effect.Statuses))]
+	target := findPokemonByID(opponentPlayer.Active[0], opponentPlayer)
+	if target == nil || opponentPlayer.Status[0].Status != StatusNone || !canHaveStatus(target, status) {
+		if statusMove {
+			currentPlayer.event("But it failed!")
+			opponentPlayer.event("But it failed!")
+		}
+		return
+	}
+
+	opponentPlayer.Status[0] = newStatus(status)
+	announce(opponentPlayer, currentPlayer, statusInflictedMessage(status))
+}
+
+// applyResidualDamage hurts a burned or poisoned Pokémon at the end of the
+// round, reporting whether that ended the battle.
+func applyResidualDamage(player, opponentPlayer *Player) bool {
+	pokemon := findPokemonByID(player.Active[0], player)
+	if pokemon == nil {
+		return false
+	}
+	damage := residualDamage(&player.Status[0], calculateStats(pokemon).HP)
+	if damage == 0 {
+		return false
+	}
+
+	player.Health[0] -= damage
+	announce(player, opponentPlayer, fmt.Sprintf("%s Remaining HP: %d", residualMessage(player.Status[0].Status), player.Health[0]))
+	if player.Health[0] <= 0 {
+		return faint(player, opponentPlayer)
+	}
+	return false
+}
+
+// faint sends out the player's next Pokémon, reporting whether they had none
+// left and so lost the battle.
+func faint(player, opponentPlayer *Player) bool {
+	if pokemon := findPokemonByID(player.Active[0], player); pokemon != nil {
+		opponentPlayer.knockouts = append(opponentPlayer.knockouts, knockout{
+			By:      opponentPlayer.Active[0],
+			Species: pokemon.NationalID,
+			Level:   level(pokemon),
+		})
+	}
+	opponentPlayer.event("Opponent's Pokémon fainted!")
+	player.event("Your Pokémon fainted!")
+	if !switchToNextPokemon(player) {
+		// End the battle if no Pokémon left to switch to
+		opponentPlayer.result(protocol.OutcomeWin, "All of the opponent's Pokémon fainted.")
+		player.result(protocol.OutcomeLose, "All of your Pokémon fainted.")
+		return true
+	}
+	return false
+}
+
+// announce tells both players what happened to the player's active Pokémon.
+func announce(player, opponentPlayer *Player, message string) {
+	name := "Pokémon"
+	if pokemon := findPokemonByID(player.Active[0], player); pokemon != nil {
+		name = pokemon.Name
+	}
+	player.event("Your %s %s", name, message)
+	opponentPlayer.event("Opponent's %s %s", name, message)
+}
+
+func calculateDamage(currentPlayer, opponentPlayer *Player, move pokedata.Move) attackResult {
+	currentPokemon := findPokemonByID(currentPlayer.Active[0], currentPlayer)
+	opponentPokemon := findPokemonByID(opponentPlayer.Active[0], opponentPlayer)
+	if currentPokemon == nil || opponentPokemon == nil {
+		return attackResult{Effectiveness: 1}
+	}
+
+	result := computeDamage(currentPokemon, opponentPokemon, move, opponentPlayer.Health[0])
+	// Burned Pokémon deal half damage with physical moves
+	if currentPlayer.Status[0].Status == StatusBurn && move.DamageClass == pokedata.DamageClassPhysical && !result.Fixed && result.Damage > 1 {
+		result.Damage /= 2
+	}
+	return result
+}
+
+func findPokemonByID(id int, player *Player) *Pokemon {
+	for i := range player.Pokemons {
+		if player.Pokemons[i].RosterID == id {
+			return &player.Pokemons[i]
+		}
+	}
+	return nil
+}
+
+func switchToNextPokemon(player *Player) bool {
+	if len(player.Active) <= 1 {
+		return false
+	}
+	player.Active = player.Active[1:]
+	player.Health = player.Health[1:]
+	player.Moves = player.Moves[1:]
+	player.Status = player.Status[1:]
+	player.fought[player.Active[0]] = true
+	return true
+}
+
+func switchPokemon(player *Player) {
+	player.Active = append(player.Active[1:], player.Active[0])
+	player.Health = append(player.Health[1:], player.Health[0])
+	player.Moves = append(player.Moves[1:], player.Moves[0])
+	// Bad poison starts counting again after switching out
+	if player.Status[0].Status == StatusToxic {
+		player.Status[0].Turns = 0
+	}
+	player.Status = append(player.Status[1:], player.Status[0])
+	player.fought[player.Active[0]] = true
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+
+	"Pokemon/pokedata"
+)
+
+// defaultLevel is given to newly added Pokémon and to roster entries saved
+// before Pokémon had levels.
+const defaultLevel = 50
+
+// struggleID is the move used once a Pokémon has run out of PP.
+const struggleID = 165
+
+type attackResult struct {
+	Damage        int
+	Effectiveness float64
+	Missed        bool
+	// Fixed is set for moves whose damage doesn't come from the damage
+	// formula, which a burn doesn't halve.
+	Fixed bool
+}
+
+// variablePower stands in for the power of damaging moves whose power depends
+// on things the battle doesn't track, such as weight or happiness.
+const variablePower = 60
+
+// fixedDamage returns the damage of moves that ignore the damage formula,
+// given the defender's remaining HP.
+func fixedDamage(attacker *Pokemon, move pokedata.Move, defenderHP int) (int, bool) {
+	switch move.Identifier {
+	case "seismic-toss", "night-shade":
+		return level(attacker), true
+	case "psywave":
+		return level(attacker) * (50 + rand.Intn(101)) / 100, true
+	case "sonic-boom":
+		return 20, true
+	case "dragon-rage":
+		return 40, true
+	case "super-fang":
+		return max(defenderHP/2, 1), true
+	case "guillotine", "horn-drill", "fissure", "sheer-cold":
+		return defenderHP, true
+	}
+	return 0, false
+}
+
+// typeMultiplier looks up how much damage a move of the given type deals to the
+// defender in the type chart.
+func typeMultiplier(moveType string, defender *Pokemon) float64 {
+	return dataset.Effectiveness(moveType, defender.TypeNames()...)
+}
+
+func hasType(pokemon *Pokemon, typeName string) bool {
+	for _, typ := range pokemon.Types {
+		if strings.EqualFold(typ.Name, typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// computeDamage applies the standard damage formula: the move's power scaled by
+// the attacker's level and the attacking and defending stats, then STAB, type effectiveness and a random
+// factor between 0.85 and 1. Status moves deal no damage, and moves with
+// fixed damage only respect type immunities. defenderHP is the defender's
+// remaining HP.
+func computeDamage(attacker, defender *Pokemon, move pokedata.Move, defenderHP int) attackResult {
+	result := attackResult{Effectiveness: 1}
+
+	if move.Accuracy != nil && rand.Intn(100) >= *move.Accuracy {
+		result.Missed = true
+		return result
+	}
+
+	if !move.Damaging() {
+		return result
+	}
+	if damage, ok := fixedDamage(attacker, move, defenderHP); ok {
+		result.Fixed = true
+		if typeMultiplier(move.TypeName, defender) == 0 {
+			result.Effectiveness = 0
+			return result
+		}
+		result.Damage = damage
+		return result
+	}
+	power := variablePower
+	if move.Power != nil {
+		power = *move.Power
+	}
+	if power <= 0 {
+		return result
+	}
+
+	attackerStats, defenderStats := calculateStats(attacker), calculateStats(defender)
+	attack, defense := attackerStats.SpAtk, defenderStats.SpDef
+	if move.DamageClass == pokedata.DamageClassPhysical {
+		attack, defense = attackerStats.Attack, defenderStats.Defense
+	}
+	if defense <= 0 {
+		defense = 1
+	}
+
+	base := float64((2*level(attacker)/5+2)*power*attack/defense)/50 + 2
+
+	modifier := 0.85 + rand.Float64()*0.15
+	if hasType(attacker, move.TypeName) {
+		modifier *= 1.5
+	}
+	result.Effectiveness = typeMultiplier(move.TypeName, defender)
+	modifier *= result.Effectiveness
+
+	result.Damage = int(base * modifier)
+	if result.Damage < 1 && result.Effectiveness > 0 {
+		result.Damage = 1
+	}
+	return result
+}
+
+func effectivenessMessage(effectiveness float64) string {
+	switch {
+	case effectiveness == 0:
+		return " It had no effect."
+	case effectiveness > 1:
+		return " It's super effective!"
+	case effectiveness < 1:
+		return " It's not very effective..."
+	}
+	return ""
+}
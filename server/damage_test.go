@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+
+	"Pokemon/pokedata"
+)
+
+// useDataset replaces the loaded data for the duration of the test.
+func useDataset(t *testing.T, d *pokedata.Dataset) {
+	t.Helper()
+	previous := dataset
+	dataset = d
+	t.Cleanup(func() { dataset = previous })
+}
+
+// testPokemon is a level 50 Pokémon with base 100 in every stat, no IVs or
+// EVs and a neutral nature, so each of its stats other than HP is 105.
+func testPokemon(nationalID int, types ...string) *Pokemon {
+	pokemon := &Pokemon{Level: 50, Nature: "Hardy"}
+	pokemon.NationalID, pokemon.Name = nationalID, "Test"
+	pokemon.HP, pokemon.Attack, pokemon.Defense = 100, 100, 100
+	pokemon.SpAtk, pokemon.SpDef, pokemon.Speed = 100, 100, 100
+	for _, typ := range types {
+		pokemon.Types = append(pokemon.Types, pokedata.ListMapObject{Name: typ})
+	}
+	return pokemon
+}
+
+// testMove never misses. Moves without power are given 0.
+func testMove(identifier, typeName string, class pokedata.DamageClass, power int) pokedata.Move {
+	move := pokedata.Move{Identifier: identifier, Name: identifier, TypeName: typeName, DamageClass: class}
+	if power > 0 {
+		move.Power = &power
+	}
+	return move
+}
+
+func TestComputeDamage(t *testing.T) {
+	useDataset(t, &pokedata.Dataset{Chart: pokedata.StandardTypeChart()})
+
+	// A power 100 move between two testPokemon has a base damage of
+	// (2*50/5+2)*100*105/105/50+2 = 46 before STAB, effectiveness and the
+	// random factor.
+	const base = 46.0
+	tests := []struct {
+		name          string
+		attacker      []string
+		defender      []string
+		move          pokedata.Move
+		effectiveness float64
+		// stab is whether the attacker shares the move's type.
+		stab bool
+		// fixed is the exact damage of moves that ignore the formula.
+		fixed int
+	}{
+		{"stab", []string{"fighting"}, []string{"normal"}, testMove("strength", "fighting", pokedata.DamageClassPhysical, 100), 2, true, 0},
+		{"no stab", []string{"fire"}, []string{"fire"}, testMove("strength", "normal", pokedata.DamageClassPhysical, 100), 1, false, 0},
+		{"super effective with stab", []string{"fire"}, []string{"grass"}, testMove("fire-blast", "fire", pokedata.DamageClassSpecial, 100), 2, true, 0},
+		{"not very effective", []string{"fire"}, []string{"grass"}, testMove("surf", "water", pokedata.DamageClassSpecial, 100), 0.5, false, 0},
+		{"double weakness", []string{"water"}, []string{"fire", "rock"}, testMove("surf", "water", pokedata.DamageClassSpecial, 100), 4, true, 0},
+		{"double resistance", []string{"fire"}, []string{"water", "rock"}, testMove("fire-blast", "fire", pokedata.DamageClassSpecial, 100), 0.25, true, 0},
+		{"immune", []string{"normal"}, []string{"ghost"}, testMove("strength", "normal", pokedata.DamageClassPhysical, 100), 0, true, 0},
+		{"status move", []string{"normal"}, []string{"normal"}, testMove("growl", "normal", pokedata.DamageClassStatus, 0), 1, false, 0},
+		{"fixed damage", []string{"fighting"}, []string{"rock"}, testMove("seismic-toss", "fighting", pokedata.DamageClassPhysical, 0), 1, false, 50},
+		{"fixed damage immune", []string{"fighting"}, []string{"ghost"}, testMove("seismic-toss", "fighting", pokedata.DamageClassPhysical, 0), 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attacker, defender := testPokemon(1, tt.attacker...), testPokemon(2, tt.defender...)
+
+			low, high := 0, 0
+			switch {
+			case tt.fixed > 0:
+				low, high = tt.fixed, tt.fixed
+			case tt.move.Damaging() && tt.effectiveness > 0:
+				modifier := tt.effectiveness
+				if tt.stab {
+					modifier *= 1.5
+				}
+				low, high = int(base*0.85*modifier), int(base*modifier)
+			}
+
+			// The random factor changes from one call to the next.
+			for i := 0; i < 100; i++ {
+				result := computeDamage(attacker, defender, tt.move, 150)
+				if result.Missed {
+					t.Fatalf("a move without accuracy missed")
+				}
+				if result.Effectiveness != tt.effectiveness {
+					t.Fatalf("Effectiveness = %v, want %v", result.Effectiveness, tt.effectiveness)
+				}
+				if result.Damage < low || result.Damage > high {
+					t.Fatalf("Damage = %d, want between %d and %d", result.Damage, low, high)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeDamageCategory(t *testing.T) {
+	useDataset(t, &pokedata.Dataset{})
+
+	// The damage class, not the type, picks the stats. Base 200 is a stat of
+	// 205 at level 50.
+	attacker, defender := testPokemon(1), testPokemon(2)
+	attacker.Attack, defender.SpDef = 200, 200
+	tests := []struct {
+		move      pokedata.Move
+		low, high int
+	}{
+		// 22*100*205/105/50+2 = 87.9
+		{testMove("strength", "normal", pokedata.DamageClassPhysical, 100), 74, 87},
+		// 22*100*105/205/50+2 = 24.52
+		{testMove("surf", "water", pokedata.DamageClassSpecial, 100), 20, 24},
+		{testMove("waterfall", "water", pokedata.DamageClassPhysical, 100), 74, 87},
+		{testMove("hyper-voice", "normal", pokedata.DamageClassSpecial, 100), 20, 24},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			result := computeDamage(attacker, defender, tt.move, 150)
+			if result.Damage < tt.low || result.Damage > tt.high {
+				t.Fatalf("%s: Damage = %d, want between %d and %d", tt.move.Name, result.Damage, tt.low, tt.high)
+			}
+		}
+	}
+}
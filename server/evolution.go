@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
+)
+
+// levelEvolution returns the species the Pokémon evolves into by leveling up,
+// if it has reached the level for it.
+func levelEvolution(pokemon *Pokemon) (pokedata.EvolutionDetail, bool) {
+	for _, target := range dataset.Evolutions[pokemon.NationalID].To {
+		if target.Method == "level_up" && target.Level > 0 && level(pokemon) >= target.Level {
+			return target, true
+		}
+	}
+	return pokedata.EvolutionDetail{}, false
+}
+
+// evolutionTimeout is how long a player has to answer an evolution prompt.
+const evolutionTimeout = 30 * time.Second
+
+// evolve asks the player whether a Pokémon that just leveled up should evolve
+// and, if they agree, turns it into the new species. It reports whether the
+// Pokémon evolved.
+func evolve(player *Player, pokemon *Pokemon) bool {
+	target, ok := levelEvolution(pokemon)
+	if !ok {
+		return false
+	}
+	into, ok := dataset.Pokemon(target.NationalID)
+	if !ok {
+		return false
+	}
+
+	if !confirmEvolution(player, protocol.Evolution{PokemonID: pokemon.RosterID, From: pokemon.Name, To: into.Name}) {
+		player.event("Your %s did not evolve.", pokemon.Name)
+		return false
+	}
+	player.event("Congratulations! Your %s evolved into %s!", pokemon.Name, into.Name)
+	fmt.Printf("%s's %s evolved into %s\n", player.Name, pokemon.Name, into.Name)
+	// The species data brings the new base stats, types and learnable moves;
+	// roster ID, level, experience, IVs, EVs and nature stay with the player's
+	// Pokémon.
+	pokemon.Pokemon = *into
+	return true
+}
+
+// confirmEvolution sends the evolution prompt and waits for the answer. A
+// player who doesn't answer within evolutionTimeout or disconnects keeps the
+// Pokémon as it is; it is offered again the next time it levels up.
+func confirmEvolution(player *Player, prompt protocol.Evolution) bool {
+	prompt.Timeout = int(evolutionTimeout / time.Second)
+	timeout := time.After(evolutionTimeout)
+
+	err := player.Conn.Send(protocol.TypeEvolution, prompt)
+	for err == nil {
+		var msg protocol.Message
+		msg, err = player.receive(timeout)
+		if err != nil {
+			break
+		}
+		if msg.Type != protocol.TypeEvolve {
+			player.sendError(fmt.Sprintf("Expected an evolve answer, got %s.", msg.Type))
+			continue
+		}
+
+		var answer protocol.Evolve
+		if decodeErr := msg.Decode(&answer); decodeErr != nil {
+			player.sendError(decodeErr.Error())
+			continue
+		}
+		return !answer.Cancel
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package main
+
+const maxLevel = 100
+
+// experienceForLevel is the total experience a Pokémon needs to reach a level.
+// The crawled data has no growth rates, so every species uses the medium fast
+// curve.
+func experienceForLevel(lvl int) int {
+	return lvl * lvl * lvl
+}
+
+// experience returns the Pokémon's total experience, treating roster entries
+// saved before experience was tracked as having just reached their level.
+func experience(pokemon *Pokemon) int {
+	if least := experienceForLevel(level(pokemon)); pokemon.Experience < least {
+		return least
+	}
+	return pokemon.Experience
+}
+
+// experienceYield is the experience earned for defeating a Pokémon in a
+// trainer battle, from the species' base experience in exp.json.
+func experienceYield(ko knockout) int {
+	return dataset.Experience[ko.Species].Base() * ko.Level * 3 / 2 / 7
+}
+
+// gainExperience adds experience to the Pokémon and levels it up through its
+// growth curve, returning how many levels it gained.
+func gainExperience(pokemon *Pokemon, amount int) int {
+	pokemon.Level = level(pokemon)
+	pokemon.Experience = experience(pokemon) + amount
+	if most := experienceForLevel(maxLevel); pokemon.Experience > most {
+		pokemon.Experience = most
+	}
+
+	gained := 0
+	for pokemon.Level < maxLevel && pokemon.Experience >= experienceForLevel(pokemon.Level+1) {
+		pokemon.Level++
+		gained++
+	}
+	return gained
+}
+
+// awardExperience shares the experience for every Pokémon the player defeated
+// between their Pokémon that were sent out and are still able to battle. It
+// returns the Pokémon that leveled up.
+func awardExperience(player *Player) []*Pokemon {
+	var participants []*Pokemon
+	for _, id := range player.Active {
+		if !player.fought[id] {
+			continue
+		}
+		if pokemon := findPokemonByID(id, player); pokemon != nil {
+			participants = append(participants, pokemon)
+		}
+	}
+	if len(participants) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, ko := range player.knockouts {
+		total += experienceYield(ko)
+	}
+	share := total / len(participants)
+	if share < 1 {
+		return nil
+	}
+
+	var leveled []*Pokemon
+	for _, pokemon := range participants {
+		gained := gainExperience(pokemon, share)
+		player.event("Your %s gained %d Exp. Points!", pokemon.Name, share)
+		if gained > 0 {
+			player.event("Your %s grew to level %d!", pokemon.Name, pokemon.Level)
+			leveled = append(leveled, pokemon)
+		}
+	}
+	return leveled
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestExperienceForLevel(t *testing.T) {
+	// The medium fast growth curve.
+	tests := []struct {
+		level, want int
+	}{
+		{1, 1},
+		{2, 8},
+		{16, 4096},
+		{50, 125000},
+		{100, 1000000},
+	}
+	for _, tt := range tests {
+		if got := experienceForLevel(tt.level); got != tt.want {
+			t.Errorf("experienceForLevel(%d) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestGainExperience(t *testing.T) {
+	tests := []struct {
+		name       string
+		level, exp int
+		amount     int
+		wantLevel  int
+		wantExp    int
+		wantGained int
+	}{
+		{"not enough to level", 15, 3375, 720, 15, 4095, 0},
+		{"exactly enough", 15, 3375, 721, 16, 4096, 1},
+		{"several levels", 15, 3375, 10000, 23, 13375, 8},
+		{"capped at level 100", 99, 970299, 100000, 100, 1000000, 1},
+		// Roster entries saved before experience was tracked start at the
+		// least experience for their level.
+		{"untracked experience", 50, 0, 100, 50, 125100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pokemon := &Pokemon{Level: tt.level, Experience: tt.exp}
+			gained := gainExperience(pokemon, tt.amount)
+			if gained != tt.wantGained || pokemon.Level != tt.wantLevel || pokemon.Experience != tt.wantExp {
+				t.Errorf("gainExperience = %d levels to level %d with %d exp, want %d levels to level %d with %d exp",
+					gained, pokemon.Level, pokemon.Experience, tt.wantGained, tt.wantLevel, tt.wantExp)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrLoggedIn = errors.New("already logged in elsewhere")
+
+// Lobby keeps track of who is logged in, pairs up players as they become ready
+// and starts a battle for each pair.
+type Lobby struct {
+	store *PlayerStore
+
+	mu      sync.Mutex
+	online  map[string]bool
+	waiting *Player
+	battles map[int]*Battle
+	nextID  int
+}
+
+func NewLobby(store *PlayerStore) *Lobby {
+	return &Lobby{store: store, online: make(map[string]bool), battles: make(map[int]*Battle)}
+}
+
+// Login starts a session for the named account, which may only have one at a
+// time.
+func (l *Lobby) Login(name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.online[name] {
+		return ErrLoggedIn
+	}
+	l.online[name] = true
+	return nil
+}
+
+// Logout ends the named account's session.
+func (l *Lobby) Logout(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.online, name)
+}
+
+// Join queues the player for a match. The first player to join waits; the next
+// one is paired with them in a new battle.
+func (l *Lobby) Join(player *Player) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.waiting == nil {
+		l.waiting = player
+		player.event("Waiting for an opponent...")
+		return
+	}
+
+	opponent := l.waiting
+	l.waiting = nil
+	l.nextID++
+	battle := newBattle(l.nextID, l.store, opponent, player)
+	l.battles[battle.ID] = battle
+	fmt.Printf("%d battle(s) in progress\n", len(l.battles))
+
+	go func() {
+		battle.Run()
+		l.finish(battle)
+	}()
+}
+
+// Waiting reports whether the player is still waiting for an opponent.
+func (l *Lobby) Waiting(player *Player) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiting == player
+}
+
+// Leave takes the player out of the queue, reporting whether they were still
+// waiting. A player who has been paired stays in their battle.
+func (l *Lobby) Leave(player *Player) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.waiting != player {
+		return false
+	}
+	l.waiting = nil
+	return true
+}
+
+func (l *Lobby) finish(battle *Battle) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.battles, battle.ID)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"Pokemon/pokedata"
+)
+
+// maxMoves is how many moves a Pokémon can know at once.
+const maxMoves = 4
+
+type MoveSlot struct {
+	Move  pokedata.Move
+	PP    int
+	MaxPP int
+}
+
+// buildMoveset returns the last four distinct moves the Pokémon learns by
+// leveling up to the given level, the same way a wild Pokémon's moves are chosen.
+func buildMoveset(pokemon *Pokemon, level int) []MoveSlot {
+	var learned []pokedata.LearnedMove
+	for _, move := range dataset.MonsterMoves[pokemon.NationalID].Moves {
+		if move.LearnType == "level up" && move.Level <= level {
+			learned = append(learned, move)
+		}
+	}
+	sort.SliceStable(learned, func(i, j int) bool {
+		return learned[i].Level < learned[j].Level
+	})
+
+	var moveset []MoveSlot
+	seen := make(map[int]bool)
+	for i := len(learned) - 1; i >= 0 && len(moveset) < maxMoves; i-- {
+		details, exists := dataset.Moves[learned[i].ID]
+		if !exists || seen[details.ID] {
+			continue
+		}
+		seen[details.ID] = true
+		pp := 0
+		if details.PP != nil {
+			pp = *details.PP
+		}
+		moveset = append(moveset, MoveSlot{Move: details, PP: pp, MaxPP: pp})
+	}
+
+	// Keep the moves in the order they were learned.
+	for i, j := 0, len(moveset)-1; i < j; i, j = i+1, j-1 {
+		moveset[i], moveset[j] = moveset[j], moveset[i]
+	}
+	return moveset
+}
+
+// findMoveSlot resolves a move name typed by a player, accepting both the
+// display name and the hyphenated identifier, or the start of a name when only
+// one move in the moveset begins with it.
+func findMoveSlot(moveset []MoveSlot, name string) int {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+	if name == "" {
+		return -1
+	}
+	for i, slot := range moveset {
+		if strings.ToLower(slot.Move.Name) == name || slot.Move.Identifier == name {
+			return i
+		}
+	}
+
+	found := -1
+	for i, slot := range moveset {
+		if strings.HasPrefix(slot.Move.Identifier, name) {
+			if found >= 0 {
+				return -1
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+func hasPP(moveset []MoveSlot) bool {
+	for _, slot := range moveset {
+		if slot.PP > 0 {
+			return true
+		}
+	}
+	return false
+}
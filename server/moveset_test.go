@@ -0,0 +1,194 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
+)
+
+func ppMove(id int, name string, pp int) pokedata.Move {
+	move := testMove(name, "normal", pokedata.DamageClassPhysical, 40)
+	move.ID, move.PP = id, &pp
+	return move
+}
+
+// discardConn is a connection that drops everything written to it and has
+// nothing to read.
+type discardConn struct{}
+
+func (discardConn) Read([]byte) (int, error)    { return 0, io.EOF }
+func (discardConn) Write(b []byte) (int, error) { return len(b), nil }
+func (discardConn) Close() error                { return nil }
+
+func TestBuildMoveset(t *testing.T) {
+	useDataset(t, &pokedata.Dataset{Moves: map[int]pokedata.Move{
+		1: ppMove(1, "tackle", 35),
+		2: ppMove(2, "growl", 40),
+		3: ppMove(3, "vine-whip", 25),
+		4: ppMove(4, "leech-seed", 10),
+		5: ppMove(5, "razor-leaf", 25),
+		6: ppMove(6, "solar-beam", 10),
+	}, MonsterMoves: map[int]pokedata.MonsterMoves{
+		1: {Moves: []pokedata.LearnedMove{
+			{LearnType: "level up", Level: 1, ID: 1},
+			{LearnType: "level up", Level: 3, ID: 2},
+			{LearnType: "level up", Level: 9, ID: 3},
+			{LearnType: "level up", Level: 7, ID: 4},
+			{LearnType: "level up", Level: 13, ID: 3},
+			{LearnType: "level up", Level: 20, ID: 5},
+			{LearnType: "machine", ID: 6},
+			{LearnType: "level up", Level: 48, ID: 6},
+			{LearnType: "level up", Level: 15, ID: 99},
+		}},
+	}})
+
+	tests := []struct {
+		level int
+		want  []string
+	}{
+		{1, []string{"tackle"}},
+		{8, []string{"tackle", "growl", "leech-seed"}},
+		// Vine Whip is learned twice but only takes one slot, and the move
+		// missing from moves.json is skipped.
+		{15, []string{"tackle", "growl", "leech-seed", "vine-whip"}},
+		{20, []string{"growl", "leech-seed", "vine-whip", "razor-leaf"}},
+		{50, []string{"leech-seed", "vine-whip", "razor-leaf", "solar-beam"}},
+	}
+	for _, tt := range tests {
+		moveset := buildMoveset(&Pokemon{Pokemon: pokedata.Pokemon{NationalID: 1}}, tt.level)
+		var got []string
+		for _, slot := range moveset {
+			got = append(got, slot.Move.Name)
+			if slot.PP != slot.MaxPP || slot.PP != *slot.Move.PP {
+				t.Errorf("level %d: %s has PP %d/%d", tt.level, slot.Move.Name, slot.PP, slot.MaxPP)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("buildMoveset at level %d = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func testMoveset() []MoveSlot {
+	return []MoveSlot{
+		{Move: pokedata.Move{Identifier: "tackle", Name: "Tackle"}, PP: 35, MaxPP: 35},
+		{Move: pokedata.Move{Identifier: "vine-whip", Name: "Vine Whip"}, PP: 0, MaxPP: 25},
+		{Move: pokedata.Move{Identifier: "leech-seed", Name: "Leech Seed"}, PP: 10, MaxPP: 10},
+	}
+}
+
+func TestFindMoveSlot(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"Tackle", 0},
+		{"tackle", 0},
+		{"Vine Whip", 1},
+		{"vine-whip", 1},
+		{"  LEECH SEED ", 2},
+		{"tack", 0},
+		{"Leech", 2},
+		{"Ember", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := findMoveSlot(testMoveset(), tt.name); got != tt.want {
+			t.Errorf("findMoveSlot(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectMove(t *testing.T) {
+	tests := []struct {
+		action  protocol.Action
+		want    int
+		wantErr bool
+	}{
+		{protocol.Action{Move: 1}, 0, false},
+		{protocol.Action{Move: 3}, 2, false},
+		{protocol.Action{MoveName: "leech seed"}, 2, false},
+		{protocol.Action{MoveName: "Tackle"}, 0, false},
+		// A name wins over a slot number.
+		{protocol.Action{Move: 1, MoveName: "leech-seed"}, 2, false},
+		{protocol.Action{}, 0, true},
+		{protocol.Action{Move: 4}, 0, true},
+		{protocol.Action{Move: -1}, 0, true},
+		{protocol.Action{MoveName: "ember"}, 0, true},
+		// Vine Whip is out of PP.
+		{protocol.Action{Move: 2}, 0, true},
+		{protocol.Action{MoveName: "vine whip"}, 0, true},
+	}
+	for _, tt := range tests {
+		tt.action.Kind = protocol.ActionMove
+		player := &Player{Moves: [][]MoveSlot{testMoveset()}}
+		got, err := selectMove(player, tt.action)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("selectMove(%+v) = %d, want an error", tt.action, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("selectMove(%+v) = %d, %v, want %d", tt.action, got, err, tt.want)
+		}
+	}
+}
+
+func TestSelectMoveStruggle(t *testing.T) {
+	moveset := testMoveset()
+	for i := range moveset {
+		moveset[i].PP = 0
+	}
+	player := &Player{Moves: [][]MoveSlot{moveset}}
+
+	// With no PP left anywhere the choice doesn't matter.
+	for _, action := range []protocol.Action{{Move: 1}, {MoveName: "ember"}, {}} {
+		action.Kind = protocol.ActionMove
+		if got, err := selectMove(player, action); got != -1 || err != nil {
+			t.Errorf("selectMove(%+v) = %d, %v, want Struggle", action, got, err)
+		}
+	}
+}
+
+func TestUseMoveSpendsPP(t *testing.T) {
+	struggle := testMove("struggle", "normal", pokedata.DamageClassPhysical, 50)
+	useDataset(t, &pokedata.Dataset{Moves: map[int]pokedata.Move{struggleID: struggle}})
+
+	newPlayer := func() *Player {
+		pokemon := testPokemon(1)
+		pokemon.RosterID = 1
+		moveset := []MoveSlot{{Move: testMove("tackle", "normal", pokedata.DamageClassPhysical, 40), PP: 2, MaxPP: 35}}
+		return &Player{
+			Pokemons: []Pokemon{*pokemon},
+			Active:   []int{1},
+			Health:   []int{1000},
+			Moves:    [][]MoveSlot{moveset},
+			Status:   make([]StatusCondition, 1),
+			Conn:     protocol.NewConn(discardConn{}),
+		}
+	}
+	attacker, defender := newPlayer(), newPlayer()
+
+	useMove(attacker, defender, 0)
+	useMove(attacker, defender, 0)
+	if pp := attacker.Moves[0][0].PP; pp != 0 {
+		t.Fatalf("PP after two uses = %d, want 0", pp)
+	}
+	if _, err := selectMove(attacker, protocol.Action{Kind: protocol.ActionMove, Move: 1}); err != nil {
+		t.Fatalf("selectMove with no PP left = %v, want Struggle", err)
+	}
+
+	// Struggle doesn't spend PP and still deals damage.
+	health := defender.Health[0]
+	useMove(attacker, defender, -1)
+	if pp := attacker.Moves[0][0].PP; pp != 0 {
+		t.Errorf("Struggle changed PP to %d", pp)
+	}
+	if defender.Health[0] >= health {
+		t.Errorf("Struggle dealt no damage")
+	}
+}
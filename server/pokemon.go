@@ -0,0 +1,17 @@
+package main
+
+import "Pokemon/pokedata"
+
+// Pokemon is a Pokémon in a player's roster: its species data and what the
+// player's Pokémon has grown into.
+type Pokemon struct {
+	pokedata.Pokemon
+	// RosterID tells apart the Pokémon in one player's roster, including two
+	// of the same species. The species is NationalID.
+	RosterID   int     `json:"roster_id"`
+	Experience int     `json:"experience"`
+	Level      int     `json:"level,omitempty"`
+	IVs        StatSet `json:"ivs"`
+	EVs        StatSet `json:"evs"`
+	Nature     string  `json:"nature,omitempty"`
+}
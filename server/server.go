@@ -1,15 +1,16 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"math/rand"
 	"net"
-	"os"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
 )
 
 type Player struct {
@@ -17,24 +18,56 @@ type Player struct {
 	Pokemons []Pokemon `json:"pokemons"`
 	Active   []int
 	Health   []int
-	Ready    bool
-	Turn     int
-	Conn     net.Conn
+	Moves    [][]MoveSlot
+	Status   []StatusCondition
+	// knockouts are the opponent's Pokémon this player defeated.
+	knockouts []knockout
+	// fought are the roster IDs of the Pokémon sent out during the battle.
+	fought map[int]bool
+	won    bool
+	Ready  bool
+	Conn   *protocol.Conn
+	// inbox carries the messages the player sends once they have joined the
+	// lobby. It is closed when their connection fails, with readErr saying
+	// why.
+	inbox   chan protocol.Message
+	readErr error
+	// done is closed once the player's battle is over.
+	done chan struct{}
 }
 
-var (
-	players []*Player
-	mu      sync.Mutex
-)
+var errTimeout = errors.New("timed out waiting for the player")
+
+// dataset is the crawled Pokémon data, loaded once at startup.
+var dataset *pokedata.Dataset
 
 func main() {
-	ln, err := net.Listen("tcp", ":8080")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	dataDir := flag.String("data", "../data", "directory containing the crawled data files")
+	playersFile := flag.String("players", "player.json", "file storing player accounts")
+	flag.Parse()
+
+	var err error
+	dataset, err = pokedata.Load(*dataDir)
+	if err != nil {
+		fmt.Println("Error loading Pokémon data:", err)
+		return
+	}
+
+	store, err := OpenPlayerStore(*playersFile)
+	if err != nil {
+		fmt.Println("Error loading player accounts:", err)
+		return
+	}
+
+	ln, err := net.Listen("tcp", *addr)
 	if err != nil {
 		fmt.Println("Error listening:", err.Error())
 		return
 	}
 	defer ln.Close()
 
+	lobby := NewLobby(store)
 	for {
 		conn, err := ln.Accept()
 		if err != nil {
@@ -43,265 +76,240 @@ func main() {
 		}
 
 		fmt.Println("Accepted new connection.")
-		go handleClient(conn)
+		go handleClient(conn, lobby, store)
 	}
 }
 
-func handleClient(conn net.Conn) {
-	defer conn.Close()
-	player := &Player{Conn: conn}
-	mu.Lock()
-	players = append(players, player)
-	mu.Unlock()
-
-	playerData, err := os.ReadFile("player.json")
-	if err != nil {
-		fmt.Println("Error reading player data file:", err)
-		return
-	}
-	var allPlayers []*Player
-	err = json.Unmarshal(playerData, &allPlayers)
-	if err != nil {
-		fmt.Println("Error unmarshaling player data:", err)
-		return
-	}
+func handleClient(conn net.Conn, lobby *Lobby, store *PlayerStore) {
+	player := &Player{Conn: protocol.NewConn(conn), inbox: make(chan protocol.Message), done: make(chan struct{})}
+	defer player.Conn.Close()
+	defer func() {
+		if player.Name != "" {
+			lobby.Logout(player.Name)
+		}
+	}()
 
-	for {
-		buffer := make([]byte, 1024)
-		n, err := conn.Read(buffer)
+	for !player.Ready {
+		msg, err := player.Conn.Receive()
+		if errors.Is(err, protocol.ErrMalformed) {
+			player.sendError(err.Error())
+			continue
+		}
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
-		message := string(buffer[:n])
-		if strings.HasPrefix(message, "Player name:") {
-			player.Name = strings.TrimSpace(message[len("Player name: "):])
-			fmt.Println("Player name is:", player.Name)
-			foundPlayer := findPlayerByName(allPlayers, player.Name)
-			if foundPlayer == nil {
-				fmt.Println("Player not found:", player.Name)
-				return
+
+		switch msg.Type {
+		case protocol.TypeLogin, protocol.TypeRegister:
+			var login protocol.Login
+			if err := msg.Decode(&login); err != nil {
+				player.sendError(err.Error())
+				continue
 			}
-			player.Pokemons = foundPlayer.Pokemons
 
-			pokemonListMessage := "\nChoose your pokemons:\n"
-			for _, pokemon := range player.Pokemons {
-				pokemonListMessage += fmt.Sprintf("%s. %s\n", pokemon.ID, pokemon.Name)
+			var account Account
+			if msg.Type == protocol.TypeRegister {
+				account, err = store.Register(login.Name, login.Password)
+			} else {
+				account, err = store.Authenticate(login.Name, login.Password)
 			}
-			conn.Write([]byte(pokemonListMessage))
+			if err == nil && account.Name != player.Name {
+				err = lobby.Login(account.Name)
+			}
+			if err != nil {
+				fmt.Printf("Failed %s for %s: %s\n", msg.Type, login.Name, err)
+				player.sendError(fmt.Sprintf("Could not %s %s: %s", msg.Type, login.Name, err))
+				continue
+			}
+			if player.Name != "" && player.Name != account.Name {
+				lobby.Logout(player.Name)
+			}
+			player.Name = account.Name
+			player.Pokemons = account.Pokemons
+			player.Active, player.Health, player.Moves, player.Status = nil, nil, nil, nil
+			fmt.Println("Player name is:", player.Name)
+
+			sendRoster(player)
 			fmt.Println("Sent pokemon list to client")
-		} else if strings.HasPrefix(message, "Player choice:") {
-			choicesStr := strings.TrimSpace(message[len("Player choice:"):])
-			choices := strings.Split(choicesStr, ",")
-			for _, choiceStr := range choices {
-				choice, err := strconv.Atoi(strings.TrimSpace(choiceStr))
-				if err != nil {
-					fmt.Println("Error converting choice to integer:", err)
+		case protocol.TypeAddPokemon, protocol.TypeRemovePokemon:
+			var change protocol.RosterChange
+			if err := msg.Decode(&change); err != nil {
+				player.sendError(err.Error())
+				continue
+			}
+			if player.Name == "" {
+				player.sendError("Log in before changing your roster.")
+				continue
+			}
+
+			var roster []Pokemon
+			if msg.Type == protocol.TypeAddPokemon {
+				pokemon := findSpecies(change)
+				if pokemon == nil {
+					suggestions := dataset.SuggestPokemon(change.Name, 3)
+					player.sendError(fmt.Sprintf("Unknown Pokémon: %s.%s", describeChange(change), didYouMean(suggestions)))
 					continue
 				}
-				player.Active = append(player.Active, choice)
-				for _, pokemon := range player.Pokemons {
-					pokemonID, _ := strconv.Atoi(pokemon.ID)
-					if pokemonID == choice {
-						player.Health = append(player.Health, pokemon.HP)
-					}
-				}
+				roster, err = store.AddPokemon(player.Name, newRosterPokemon(*pokemon))
+			} else {
+				roster, err = store.RemovePokemon(player.Name, change.ID)
+			}
+			if err != nil {
+				player.sendError(err.Error())
+				continue
+			}
+			player.Pokemons = roster
+			player.Active, player.Health, player.Moves, player.Status = nil, nil, nil, nil
+			sendRoster(player)
+		case protocol.TypeChoose:
+			var choose protocol.Choose
+			if err := msg.Decode(&choose); err != nil {
+				player.sendError(err.Error())
+				continue
+			}
+			if err := chooseTeam(player, choose.PokemonIDs); err != nil {
+				player.sendError(err.Error())
+				continue
 			}
 			fmt.Println("Player's active choices:", player.Active)
-		} else if strings.TrimSpace(message) == "ready" {
+		case protocol.TypeReady:
+			if len(player.Active) == 0 {
+				player.sendError("Choose your Pokémon before getting ready.")
+				continue
+			}
 			player.Ready = true
-			break
+			player.event("You are ready. Please wait for the match to start.")
+		default:
+			player.sendError(fmt.Sprintf("Unexpected %s message before the battle.", msg.Type))
 		}
 	}
 
-	waitForAllPlayersReady()
-	startBattle()
-}
-
-func findPlayerByName(players []*Player, name string) *Player {
-	for _, p := range players {
-		if p.Name == name {
-			return p
-		}
-	}
-	return nil
+	lobby.Join(player)
+	go readMessages(player, lobby)
+	<-player.done
 }
 
-func waitForAllPlayersReady() {
+// readMessages passes on what the player sends once they are in the lobby. A
+// player who disconnects while waiting for an opponent leaves the lobby; one
+// who disconnects during a battle forfeits it.
+func readMessages(player *Player, lobby *Lobby) {
 	for {
-		mu.Lock()
-		allReady := true
-		for _, currentPlayer := range players {
-			if !currentPlayer.Ready {
-				allReady = false
-				break
-			}
-		}
-		mu.Unlock()
-		if allReady {
-			return
+		msg, err := player.Conn.Receive()
+		if errors.Is(err, protocol.ErrMalformed) {
+			player.sendError(err.Error())
+			continue
 		}
-		time.Sleep(100 * time.Millisecond) // Reduce busy-waiting CPU usage
-	}
-}
-
-func startBattle() {
-	mu.Lock()
-	defer mu.Unlock()
-	fmt.Println("Battle start")
-
-	assignInitialTurns()
-
-	// Start with player whose Turn is 1
-	currentPlayer := findPlayerWithTurn(1)
-	opponentPlayer := findPlayerWithTurn(2)
-
-	for {
-		// Send turn message to current player
-		_, err := currentPlayer.Conn.Write([]byte("Your turn! Choose an action:\nSwitch: {pokemon ID}\nAttack\nForfeit\n"))
 		if err != nil {
-			fmt.Println("Error writing to current player:", err)
+			player.readErr = err
+			close(player.inbox)
+			if lobby.Leave(player) {
+				fmt.Printf("%s left the lobby: %s\n", player.Name, err)
+				close(player.done)
+			}
 			return
 		}
+		if lobby.Waiting(player) {
+			player.sendError("Waiting for an opponent.")
+			continue
+		}
 
-		// Read action from current player
-		buffer := make([]byte, 1024)
-		n, err := currentPlayer.Conn.Read(buffer)
-		if err != nil {
-			fmt.Println("Error reading from current player:", err)
+		select {
+		case player.inbox <- msg:
+		case <-player.done:
 			return
 		}
-		action := strings.TrimSpace(string(buffer[:n]))
-		processAction(currentPlayer, opponentPlayer, action)
-
-		// Switch turn to the opponent
-		currentPlayer, opponentPlayer = opponentPlayer, currentPlayer
 	}
 }
 
-func findPlayerWithTurn(turn int) *Player {
-	for _, p := range players {
-		if p.Turn == turn {
-			return p
+// receive returns the next message the player sends. It gives up with
+// errTimeout once timeout fires; a nil timeout waits as long as it takes.
+func (p *Player) receive(timeout <-chan time.Time) (protocol.Message, error) {
+	select {
+	case msg, ok := <-p.inbox:
+		if !ok {
+			return msg, p.readErr
 		}
+		return msg, nil
+	case <-timeout:
+		return protocol.Message{}, errTimeout
 	}
-	return nil
 }
 
-func assignInitialTurns() {
-	player1 := players[0]
-	player2 := players[1]
-
-	player1Speed := getSpeedOfFirstActivePokemon(player1)
-	player2Speed := getSpeedOfFirstActivePokemon(player2)
-
-	if player1Speed >= player2Speed {
-		player1.Turn = 1 // Assign turn 1 to player 1
-		player2.Turn = 2 // Assign turn 2 to player 2
-	} else {
-		player1.Turn = 2 // Assign turn 2 to player 1
-		player2.Turn = 1 // Assign turn 1 to player 2
+func sendRoster(player *Player) {
+	roster := protocol.Roster{Pokemons: []protocol.RosterEntry{}}
+	for _, pokemon := range player.Pokemons {
+		roster.Pokemons = append(roster.Pokemons, protocol.RosterEntry{ID: pokemon.RosterID, Name: pokemon.Name, Level: level(&pokemon)})
 	}
+	player.Conn.Send(protocol.TypeRoster, roster)
 }
 
-func getSpeedOfFirstActivePokemon(player *Player) int {
-	firstActiveID := strconv.Itoa(player.Active[0])
-	for _, pokemon := range player.Pokemons {
-		if pokemon.ID == firstActiveID {
-			fmt.Printf("%s's first pokemon speed is: %d\n", player.Name, pokemon.Speed)
-			return pokemon.Speed
-		}
+// findSpecies looks up the Pokémon named by a roster change, by name if one
+// was given and by national ID otherwise.
+func findSpecies(change protocol.RosterChange) *pokedata.Pokemon {
+	if change.Name != "" {
+		pokemon, _ := dataset.PokemonByName(change.Name)
+		return pokemon
 	}
-	return 0
+	pokemon, _ := dataset.Pokemon(change.ID)
+	return pokemon
 }
 
-func processAction(currentPlayer, opponentPlayer *Player, action string) {
-	switch strings.ToLower(action) {
-	case "attack":
-		rand.Seed(time.Now().UnixNano())
-		damage := calculateDamage(currentPlayer, opponentPlayer)
-		opponentPlayer.Health[0] -= damage
-		fmt.Printf("%s attacked and dealt %d damage to %s's first Pokémon. Remaining HP: %d\n", currentPlayer.Name, damage, opponentPlayer.Name, opponentPlayer.Health[0])
-
-		// Notify players
-		currentPlayer.Conn.Write([]byte(fmt.Sprintf("You attacked and dealt %d damage. Opponent's Pokémon remaining HP: %d\n", damage, opponentPlayer.Health[0])))
-		opponentPlayer.Conn.Write([]byte(fmt.Sprintf("Opponent attacked and dealt %d damage to your Pokémon. Remaining HP: %d\n", damage, opponentPlayer.Health[0])))
-
-		// Check if opponent's Pokémon fainted
-		if opponentPlayer.Health[0] <= 0 {
-			currentPlayer.Conn.Write([]byte("Opponent's Pokémon fainted!\n"))
-			opponentPlayer.Conn.Write([]byte("Your Pokémon fainted!\n"))
-			if !switchToNextPokemon(opponentPlayer) {
-				// End the battle if no Pokémon left to switch to
-				currentPlayer.Conn.Write([]byte("You win!\n"))
-				opponentPlayer.Conn.Write([]byte("You lose!\n"))
-				currentPlayer.Conn.Close()
-				opponentPlayer.Conn.Close()
-				return
-			}
-		}
-	case "switch":
-		// Handle Pokémon switching logic
-		if len(currentPlayer.Active) < 2 {
-			currentPlayer.Conn.Write([]byte("No other Pokémon to switch to.\n"))
-			return
-		}
-		switchPokemon(currentPlayer)
-		currentPlayer.Conn.Write([]byte("You switched Pokémon. Turn passed to opponent.\n"))
-		opponentPlayer.Conn.Write([]byte("Opponent switched Pokémon. It's your turn.\n"))
-	case "forfeit":
-		// Handle player forfeiting the match
-		currentPlayer.Conn.Write([]byte("You forfeited. You lose!\n"))
-		opponentPlayer.Conn.Write([]byte("Opponent forfeited. You win!\n"))
-		currentPlayer.Conn.Close()
-		opponentPlayer.Conn.Close()
+func describeChange(change protocol.RosterChange) string {
+	if change.Name != "" {
+		return change.Name
 	}
+	return strconv.Itoa(change.ID)
 }
-func calculateDamage(currentPlayer, opponentPlayer *Player) int {
-	currentPokemon := findPokemonByID(currentPlayer.Active[0], currentPlayer)
-	opponentPokemon := findPokemonByID(opponentPlayer.Active[0], opponentPlayer)
-	if currentPokemon == nil || opponentPokemon == nil {
-		return 0
-	}
-
-	source := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(source)
-	attackType := r.Intn(2)
 
-	var damage int
-	if attackType == 0 {
-		// Normal attack
-		damage = currentPokemon.Attack - opponentPokemon.Defense
-	} else {
-		// Special attack
-		damage = currentPokemon.SpAtk*2 - opponentPokemon.SpDef
+// didYouMean turns suggestions into a sentence to append to an error message,
+// or nothing if there are none.
+func didYouMean(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(" Did you mean %s?", suggestions[0])
 	}
+	last := len(suggestions) - 1
+	return fmt.Sprintf(" Did you mean %s or %s?", strings.Join(suggestions[:last], ", "), suggestions[last])
+}
 
-	if damage < 0 {
-		damage = 0
+// chooseTeam replaces the player's battle team with the given roster IDs.
+func chooseTeam(player *Player, ids []int) error {
+	if player.Name == "" {
+		return errors.New("Log in before choosing Pokémon.")
+	}
+	if len(ids) == 0 || len(ids) > pokedata.MaxTeamSize {
+		return fmt.Errorf("Choose between 1 and %d Pokémon.", pokedata.MaxTeamSize)
 	}
 
-	return damage
-}
-func findPokemonByID(id int, player *Player) *Pokemon {
-	for _, pokemon := range player.Pokemons {
-		if pokemon.ID == strconv.Itoa(id) {
-			return &pokemon
+	var active, health []int
+	var moves [][]MoveSlot
+	for _, id := range ids {
+		pokemon := findPokemonByID(id, player)
+		if pokemon == nil {
+			return fmt.Errorf("You don't have a Pokémon with ID %d.", id)
 		}
+		active = append(active, id)
+		health = append(health, calculateStats(pokemon).HP)
+		moves = append(moves, buildMoveset(pokemon, level(pokemon)))
 	}
+	player.Active, player.Health, player.Moves = active, health, moves
+	player.Status = make([]StatusCondition, len(active))
+	player.fought = map[int]bool{active[0]: true}
 	return nil
 }
 
-func switchToNextPokemon(player *Player) bool {
-	if len(player.Active) <= 1 {
-		return false
-	}
-	player.Active = player.Active[1:]
-	player.Health = player.Health[1:]
-	return true
+func (p *Player) event(format string, args ...interface{}) {
+	p.Conn.Send(protocol.TypeEvent, protocol.Event{Text: fmt.Sprintf(format, args...)})
+}
+
+func (p *Player) sendError(message string) {
+	p.Conn.Send(protocol.TypeError, protocol.Error{Message: message})
 }
 
-func switchPokemon(player *Player) {
-	player.Active = append(player.Active[1:], player.Active[0])
-	player.Health = append(player.Health[1:], player.Health[0])
+func (p *Player) result(outcome, reason string) {
+	p.won = outcome == protocol.OutcomeWin
+	p.Conn.Send(protocol.TypeResult, protocol.Result{Outcome: outcome, Reason: reason})
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"math/rand"
+
+	"Pokemon/pokedata"
+)
+
+const (
+	maxIV       = 31
+	maxStatEV   = 252
+	maxTotalEVs = 510
+)
+
+// StatSet holds one value per stat, used for IVs, EVs and calculated stats.
+type StatSet struct {
+	HP      int `json:"hp"`
+	Attack  int `json:"attack"`
+	Defense int `json:"defense"`
+	SpAtk   int `json:"sp_atk"`
+	SpDef   int `json:"sp_def"`
+	Speed   int `json:"speed"`
+}
+
+func (s StatSet) total() int {
+	return s.HP + s.Attack + s.Defense + s.SpAtk + s.SpDef + s.Speed
+}
+
+// nature raises one stat by 10% and lowers another by 10%. Neutral natures
+// raise and lower the same stat.
+type nature struct {
+	Increased string
+	Decreased string
+}
+
+var natures = map[string]nature{
+	"Hardy":   {"attack", "attack"},
+	"Lonely":  {"attack", "defense"},
+	"Brave":   {"attack", "speed"},
+	"Adamant": {"attack", "sp_atk"},
+	"Naughty": {"attack", "sp_def"},
+	"Bold":    {"defense", "attack"},
+	"Docile":  {"defense", "defense"},
+	"Relaxed": {"defense", "speed"},
+	"Impish":  {"defense", "sp_atk"},
+	"Lax":     {"defense", "sp_def"},
+	"Timid":   {"speed", "attack"},
+	"Hasty":   {"speed", "defense"},
+	"Serious": {"speed", "speed"},
+	"Jolly":   {"speed", "sp_atk"},
+	"Naive":   {"speed", "sp_def"},
+	"Modest":  {"sp_atk", "attack"},
+	"Mild":    {"sp_atk", "defense"},
+	"Quiet":   {"sp_atk", "speed"},
+	"Bashful": {"sp_atk", "sp_atk"},
+	"Rash":    {"sp_atk", "sp_def"},
+	"Calm":    {"sp_def", "attack"},
+	"Gentle":  {"sp_def", "defense"},
+	"Sassy":   {"sp_def", "speed"},
+	"Careful": {"sp_def", "sp_atk"},
+	"Quirky":  {"sp_def", "sp_def"},
+}
+
+func natureModifier(natureName, stat string) float64 {
+	n, exists := natures[natureName]
+	if !exists || n.Increased == n.Decreased {
+		return 1
+	}
+	switch stat {
+	case n.Increased:
+		return 1.1
+	case n.Decreased:
+		return 0.9
+	}
+	return 1
+}
+
+// level returns the Pokémon's level, treating roster entries saved before
+// levels existed as defaultLevel.
+func level(pokemon *Pokemon) int {
+	if pokemon.Level <= 0 {
+		return defaultLevel
+	}
+	return pokemon.Level
+}
+
+// calculateStats derives a Pokémon's actual stats from its base stats, level,
+// IVs, EVs and nature.
+func calculateStats(pokemon *Pokemon) StatSet {
+	lvl := level(pokemon)
+	stat := func(name string, base, iv, ev int) int {
+		value := (2*base+iv+ev/4)*lvl/100 + 5
+		return int(float64(value) * natureModifier(pokemon.Nature, name))
+	}
+
+	return StatSet{
+		HP:      (2*pokemon.HP+pokemon.IVs.HP+pokemon.EVs.HP/4)*lvl/100 + lvl + 10,
+		Attack:  stat("attack", pokemon.Attack, pokemon.IVs.Attack, pokemon.EVs.Attack),
+		Defense: stat("defense", pokemon.Defense, pokemon.IVs.Defense, pokemon.EVs.Defense),
+		SpAtk:   stat("sp_atk", pokemon.SpAtk, pokemon.IVs.SpAtk, pokemon.EVs.SpAtk),
+		SpDef:   stat("sp_def", pokemon.SpDef, pokemon.IVs.SpDef, pokemon.EVs.SpDef),
+		Speed:   stat("speed", pokemon.Speed, pokemon.IVs.Speed, pokemon.EVs.Speed),
+	}
+}
+
+// newRosterPokemon turns a species into a Pokémon a player can own, with
+// random IVs and nature.
+func newRosterPokemon(species pokedata.Pokemon) Pokemon {
+	pokemon := Pokemon{Pokemon: species}
+	pokemon.Level = defaultLevel
+	pokemon.IVs = StatSet{
+		HP:      rand.Intn(maxIV + 1),
+		Attack:  rand.Intn(maxIV + 1),
+		Defense: rand.Intn(maxIV + 1),
+		SpAtk:   rand.Intn(maxIV + 1),
+		SpDef:   rand.Intn(maxIV + 1),
+		Speed:   rand.Intn(maxIV + 1),
+	}
+	names := make([]string, 0, len(natures))
+	for name := range natures {
+		names = append(names, name)
+	}
+	pokemon.Nature = names[rand.Intn(len(names))]
+	return pokemon
+}
+
+// evYield is the EVs earned for defeating a species, from stats.json.
+func evYield(nationalID int) StatSet {
+	info, exists := dataset.Additional[nationalID]
+	if !exists {
+		return StatSet{}
+	}
+	return StatSet{
+		HP:      info.HPEV,
+		Attack:  info.AttackEV,
+		Defense: info.DefenseEV,
+		SpAtk:   info.SpecialAttackEV,
+		SpDef:   info.SpecialDefenseEV,
+		Speed:   info.SpeedEV,
+	}
+}
+
+// gainEVs adds an EV yield to the Pokémon, respecting the per-stat and total caps.
+func gainEVs(pokemon *Pokemon, yield StatSet) {
+	add := func(ev *int, amount int) {
+		if room := maxTotalEVs - pokemon.EVs.total(); amount > room {
+			amount = room
+		}
+		if room := maxStatEV - *ev; amount > room {
+			amount = room
+		}
+		if amount > 0 {
+			*ev += amount
+		}
+	}
+	add(&pokemon.EVs.HP, yield.HP)
+	add(&pokemon.EVs.Attack, yield.Attack)
+	add(&pokemon.EVs.Defense, yield.Defense)
+	add(&pokemon.EVs.SpAtk, yield.SpAtk)
+	add(&pokemon.EVs.SpDef, yield.SpDef)
+	add(&pokemon.EVs.Speed, yield.Speed)
+}
+
+// knockout records that a player's Pokémon defeated one of the opponent's.
+type knockout struct {
+	By      int
+	Species int
+	Level   int
+}
+
+// awardEVs applies the EV yield of every Pokémon the player defeated to the
+// Pokémon that defeated it.
+func awardEVs(player *Player) {
+	for _, ko := range player.knockouts {
+		pokemon := findPokemonByID(ko.By, player)
+		if pokemon == nil {
+			continue
+		}
+		gainEVs(pokemon, evYield(ko.Species))
+	}
+}
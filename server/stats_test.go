@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestCalculateStats(t *testing.T) {
+	tests := []struct {
+		name string
+		// base is HP, Attack, Defense, Sp. Atk, Sp. Def and Speed.
+		base   StatSet
+		level  int
+		ivs    StatSet
+		evs    StatSet
+		nature string
+		want   StatSet
+	}{
+		// The worked example from Bulbapedia's stat article.
+		{"garchomp", StatSet{108, 130, 95, 80, 85, 102}, 78,
+			StatSet{24, 12, 30, 16, 23, 5}, StatSet{74, 190, 91, 48, 84, 23}, "Adamant",
+			StatSet{289, 278, 193, 135, 171, 171}},
+		{"neutral nature", StatSet{35, 55, 40, 50, 50, 90}, 100,
+			StatSet{31, 31, 31, 31, 31, 31}, StatSet{}, "Hardy",
+			StatSet{211, 146, 116, 136, 136, 216}},
+		// Roster entries saved before levels existed are level 50.
+		{"default level", StatSet{35, 55, 40, 50, 50, 90}, 0,
+			StatSet{31, 31, 31, 31, 31, 31}, StatSet{Speed: 252}, "Timid",
+			StatSet{110, 67, 60, 70, 70, 156}},
+		{"unknown nature", StatSet{35, 55, 40, 50, 50, 90}, 100,
+			StatSet{31, 31, 31, 31, 31, 31}, StatSet{}, "",
+			StatSet{211, 146, 116, 136, 136, 216}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pokemon := &Pokemon{Level: tt.level, IVs: tt.ivs, EVs: tt.evs, Nature: tt.nature}
+			pokemon.HP, pokemon.Attack, pokemon.Defense = tt.base.HP, tt.base.Attack, tt.base.Defense
+			pokemon.SpAtk, pokemon.SpDef, pokemon.Speed = tt.base.SpAtk, tt.base.SpDef, tt.base.Speed
+			if got := calculateStats(pokemon); got != tt.want {
+				t.Errorf("calculateStats = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"math/rand"
+
+	"Pokemon/pokedata"
+)
+
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusBurn      Status = "burn"
+	StatusPoison    Status = "poison"
+	StatusToxic     Status = "toxic"
+	StatusParalysis Status = "paralysis"
+	StatusSleep     Status = "sleep"
+	StatusFreeze    Status = "freeze"
+)
+
+// StatusCondition is the major status of one battling Pokémon. Turns counts
+// down the remaining sleep turns, or up the number of turns badly poisoned.
+type StatusCondition struct {
+	Status Status
+	Turns  int
+}
+
+// statusEffect is the status a move can inflict. When several statuses are
+// listed one of them is picked at random.
+type statusEffect struct {
+	Statuses []Status
+	Chance   int
+}
+
+// Types that can never be given a status.
+var statusImmunities = map[Status][]string{
+	StatusBurn:   {"fire"},
+	StatusPoison: {"poison", "steel"},
+	StatusToxic:  {"poison", "steel"},
+	StatusFreeze: {"ice"},
+}
+
+// moveStatusEffect returns the status a move inflicts, as parsed from its
+// description in moves.json.
+func moveStatusEffect(move pokedata.Move) (statusEffect, bool) {
+	if move.Effects.Status == nil {
+		return statusEffect{}, false
+	}
+	effect := statusEffect{Chance: move.Effects.Status.Chance}
+	for _, status := range move.Effects.Status.Statuses {
+		effect.Statuses = append(effect.Statuses, Status(status))
+	}
+	return effect, true
+}
+
+func canHaveStatus(pokemon *Pokemon, status Status) bool {
+	for _, typeName := range statusImmunities[status] {
+		if hasType(pokemon, typeName) {
+			return false
+		}
+	}
+	return true
+}
+
+// newStatus starts a status condition, rolling how long a Pokémon sleeps for.
+func newStatus(status Status) StatusCondition {
+	condition := StatusCondition{Status: status}
+	if status == StatusSleep {
+		condition.Turns = 1 + rand.Intn(3)
+	}
+	return condition
+}
+
+func statusInflictedMessage(status Status) string {
+	switch status {
+	case StatusBurn:
+		return "was burned!"
+	case StatusPoison:
+		return "was poisoned!"
+	case StatusToxic:
+		return "was badly poisoned!"
+	case StatusParalysis:
+		return "is paralyzed! It may be unable to move!"
+	case StatusSleep:
+		return "fell asleep!"
+	case StatusFreeze:
+		return "was frozen solid!"
+	}
+	return ""
+}
+
+// residualDamage is the damage a status deals at the end of each turn.
+func residualDamage(condition *StatusCondition, maxHP int) int {
+	var damage int
+	switch condition.Status {
+	case StatusBurn, StatusPoison:
+		damage = maxHP / 8
+	case StatusToxic:
+		condition.Turns++
+		damage = maxHP * condition.Turns / 16
+	default:
+		return 0
+	}
+	if damage < 1 {
+		damage = 1
+	}
+	return damage
+}
+
+func residualMessage(status Status) string {
+	if status == StatusBurn {
+		return "is hurt by its burn."
+	}
+	return "is hurt by poison."
+}
+
+// checkCanMove decides whether a Pokémon's status lets it act this turn,
+// updating sleep and freeze as it goes. The message describes what happened.
+func checkCanMove(condition *StatusCondition) (bool, string) {
+	switch condition.Status {
+	case StatusSleep:
+		if condition.Turns <= 0 {
+			*condition = StatusCondition{}
+			return true, "woke up!"
+		}
+		condition.Turns--
+		return false, "is fast asleep."
+	case StatusFreeze:
+		if rand.Intn(5) == 0 {
+			*condition = StatusCondition{}
+			return true, "thawed out!"
+		}
+		return false, "is frozen solid!"
+	case StatusParalysis:
+		if rand.Intn(4) == 0 {
+			return false, "is paralyzed! It can't move!"
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	hashIterations = 100000
+	hashSize       = sha256.Size
+	saltSize       = 16
+)
+
+var (
+	ErrAccountExists    = errors.New("account already exists")
+	ErrAccountNotFound  = errors.New("account not found")
+	ErrBadPassword      = errors.New("wrong password")
+	ErrPasswordRequired = errors.New("a password is required")
+)
+
+// Account is a registered player as stored on disk. Accounts saved without a
+// password hash take the password of their next login as their own.
+type Account struct {
+	Name         string    `json:"name"`
+	PasswordHash string    `json:"password_hash,omitempty"`
+	Salt         string    `json:"salt,omitempty"`
+	Pokemons     []Pokemon `json:"pokemons"`
+	// NextRosterID is the roster ID the next Pokémon added gets. It only
+	// grows, so a removed Pokémon's ID is never given to another.
+	NextRosterID int `json:"next_roster_id"`
+}
+
+// PlayerStore keeps player accounts in a JSON file, rewriting it atomically
+// after every change.
+type PlayerStore struct {
+	path     string
+	mu       sync.Mutex
+	accounts []*Account
+}
+
+// OpenPlayerStore loads the accounts in path. A missing file is an empty store.
+func OpenPlayerStore(path string) (*PlayerStore, error) {
+	store := &PlayerStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.accounts); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	changed := false
+	for _, account := range store.accounts {
+		if assignRosterIDs(account) {
+			changed = true
+		}
+	}
+	if changed {
+		if err := store.save(); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// Register creates an account protected by the password.
+func (s *PlayerStore) Register(name, password string) (Account, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Account{}, errors.New("name must not be empty")
+	}
+	if password == "" {
+		return Account{}, ErrPasswordRequired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(name) != nil {
+		return Account{}, ErrAccountExists
+	}
+	account := &Account{Name: name, Pokemons: []Pokemon{}, NextRosterID: 1}
+	if err := setPassword(account, password); err != nil {
+		return Account{}, err
+	}
+
+	s.accounts = append(s.accounts, account)
+	if err := s.save(); err != nil {
+		s.accounts = s.accounts[:len(s.accounts)-1]
+		return Account{}, err
+	}
+	return copyAccount(account), nil
+}
+
+// Authenticate returns the named account if the password matches. An account
+// without a password takes this one as its own.
+func (s *PlayerStore) Authenticate(name, password string) (Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account := s.find(name)
+	if account == nil {
+		return Account{}, ErrAccountNotFound
+	}
+	if account.PasswordHash == "" {
+		if password == "" {
+			return Account{}, fmt.Errorf("%w: %s has none yet, so log in with the one to set", ErrPasswordRequired, account.Name)
+		}
+		previous := *account
+		if err := setPassword(account, password); err != nil {
+			return Account{}, err
+		}
+		if err := s.save(); err != nil {
+			*account = previous
+			return Account{}, err
+		}
+		return copyAccount(account), nil
+	}
+
+	salt, err := hex.DecodeString(account.Salt)
+	if err != nil {
+		return Account{}, fmt.Errorf("corrupt salt for %s: %w", account.Name, err)
+	}
+	want, err := hex.DecodeString(account.PasswordHash)
+	if err != nil {
+		return Account{}, fmt.Errorf("corrupt password hash for %s: %w", account.Name, err)
+	}
+	if subtle.ConstantTimeCompare(hashPassword(password, salt), want) != 1 {
+		return Account{}, ErrBadPassword
+	}
+	return copyAccount(account), nil
+}
+
+// AddPokemon puts a Pokémon in the player's roster under a new roster ID and
+// returns the new roster.
+func (s *PlayerStore) AddPokemon(name string, pokemon Pokemon) ([]Pokemon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account := s.find(name)
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	pokemon.RosterID = account.NextRosterID
+	previous := account.Pokemons
+	account.Pokemons = append(append([]Pokemon{}, previous...), pokemon)
+	account.NextRosterID++
+	if err := s.save(); err != nil {
+		account.Pokemons = previous
+		account.NextRosterID--
+		return nil, err
+	}
+	return copyAccount(account).Pokemons, nil
+}
+
+// RemovePokemon takes the Pokémon with the given roster ID out of the
+// player's roster and returns the new roster.
+func (s *PlayerStore) RemovePokemon(name string, id int) ([]Pokemon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account := s.find(name)
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	previous := account.Pokemons
+	var remaining []Pokemon
+	for _, owned := range previous {
+		if owned.RosterID != id {
+			remaining = append(remaining, owned)
+		}
+	}
+	if len(remaining) == len(previous) {
+		return nil, fmt.Errorf("no Pokémon with ID %d in the roster", id)
+	}
+
+	account.Pokemons = remaining
+	if err := s.save(); err != nil {
+		account.Pokemons = previous
+		return nil, err
+	}
+	return copyAccount(account).Pokemons, nil
+}
+
+// UpdateRoster saves changes to Pokémon already in the player's roster, such
+// as EVs and experience earned in a battle. Pokémon no longer in the roster
+// are ignored.
+func (s *PlayerStore) UpdateRoster(name string, pokemons []Pokemon) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account := s.find(name)
+	if account == nil {
+		return ErrAccountNotFound
+	}
+
+	previous := account.Pokemons
+	updated := append([]Pokemon{}, previous...)
+	for i, owned := range updated {
+		for _, pokemon := range pokemons {
+			if pokemon.RosterID == owned.RosterID {
+				updated[i] = pokemon
+			}
+		}
+	}
+
+	account.Pokemons = updated
+	if err := s.save(); err != nil {
+		account.Pokemons = previous
+		return err
+	}
+	return nil
+}
+
+func (s *PlayerStore) find(name string) *Account {
+	for _, account := range s.accounts {
+		if strings.EqualFold(account.Name, strings.TrimSpace(name)) {
+			return account
+		}
+	}
+	return nil
+}
+
+// save writes the store to a temporary file next to the real one and renames
+// it into place, so a crash never leaves a half-written file behind.
+func (s *PlayerStore) save() error {
+	data, err := json.MarshalIndent(s.accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// assignRosterIDs numbers the Pokémon saved before roster IDs existed, which
+// were told apart by species alone, and starts the roster ID counter of
+// accounts saved before it existed past every ID in use. It reports whether
+// it changed the account.
+func assignRosterIDs(account *Account) bool {
+	changed := false
+	for _, pokemon := range account.Pokemons {
+		if pokemon.RosterID >= account.NextRosterID {
+			account.NextRosterID = pokemon.RosterID + 1
+			changed = true
+		}
+	}
+	if account.NextRosterID < 1 {
+		account.NextRosterID = 1
+		changed = true
+	}
+	for i := range account.Pokemons {
+		if account.Pokemons[i].RosterID == 0 {
+			account.Pokemons[i].RosterID = account.NextRosterID
+			account.NextRosterID++
+			changed = true
+		}
+	}
+	return changed
+}
+
+func copyAccount(account *Account) Account {
+	copied := *account
+	copied.Pokemons = append([]Pokemon{}, account.Pokemons...)
+	return copied
+}
+
+// setPassword gives the account a new salt and the hash of the password.
+func setPassword(account *Account, password string) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	account.Salt = hex.EncodeToString(salt)
+	account.PasswordHash = hex.EncodeToString(hashPassword(password, salt))
+	return nil
+}
+
+// hashPassword derives a key from the password with PBKDF2-HMAC-SHA256.
+func hashPassword(password string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(password), salt, hashIterations, hashSize, sha256.New)
+}
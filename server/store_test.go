@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"Pokemon/pokedata"
+)
+
+func openStore(t *testing.T, path string) *PlayerStore {
+	t.Helper()
+	store, err := OpenPlayerStore(path)
+	if err != nil {
+		t.Fatalf("OpenPlayerStore: %v", err)
+	}
+	return store
+}
+
+func rosterNames(pokemons []Pokemon) []string {
+	names := []string{}
+	for _, pokemon := range pokemons {
+		names = append(names, pokemon.Name)
+	}
+	return names
+}
+
+func TestPlayerStoreAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.json")
+	store := openStore(t, path)
+
+	if _, err := store.Register("Ash", "pikachu"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := store.Register("Misty", ""); !errors.Is(err, ErrPasswordRequired) {
+		t.Errorf("Register without a password: err = %v, want ErrPasswordRequired", err)
+	}
+	if _, err := store.Register(" ash ", "other"); !errors.Is(err, ErrAccountExists) {
+		t.Errorf("Register of a taken name: err = %v, want ErrAccountExists", err)
+	}
+	if _, err := store.Register("  ", "x"); err == nil {
+		t.Errorf("Register of an empty name succeeded")
+	}
+
+	// Everything is read back from the file.
+	store = openStore(t, path)
+	tests := []struct {
+		name, password string
+		wantErr        error
+	}{
+		{"Ash", "pikachu", nil},
+		{"ASH", "pikachu", nil},
+		{"Ash", "raichu", ErrBadPassword},
+		{"Ash", "", ErrBadPassword},
+		{"Misty", "starmie", ErrAccountNotFound},
+		{"Brock", "onix", ErrAccountNotFound},
+	}
+	for _, tt := range tests {
+		account, err := store.Authenticate(tt.name, tt.password)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Authenticate(%q, %q): err = %v, want %v", tt.name, tt.password, err, tt.wantErr)
+			continue
+		}
+		if err == nil && account.Name != "Ash" {
+			t.Errorf("Authenticate(%q) returned account %q", tt.name, account.Name)
+		}
+	}
+}
+
+func TestPlayerStoreRoster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.json")
+	store := openStore(t, path)
+	if _, err := store.Register("Ash", "pikachu"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	pikachu := Pokemon{Pokemon: pokedata.Pokemon{ID: "25", NationalID: 25, Name: "Pikachu"}}
+	bulbasaur := Pokemon{Pokemon: pokedata.Pokemon{ID: "1", NationalID: 1, Name: "Bulbasaur"}}
+	// A player may own two of the same species; each gets its own roster ID.
+	var roster []Pokemon
+	for _, pokemon := range []Pokemon{pikachu, bulbasaur, pikachu} {
+		var err error
+		if roster, err = store.AddPokemon("Ash", pokemon); err != nil {
+			t.Fatalf("AddPokemon(%s): %v", pokemon.Name, err)
+		}
+	}
+	for i, pokemon := range roster {
+		if pokemon.RosterID != i+1 {
+			t.Errorf("%s has roster ID %d, want %d", pokemon.Name, pokemon.RosterID, i+1)
+		}
+	}
+	if _, err := store.AddPokemon("Gary", pikachu); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("AddPokemon for a missing account: err = %v, want ErrAccountNotFound", err)
+	}
+
+	store = openStore(t, path)
+	account, err := store.Authenticate("Ash", "pikachu")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if got := rosterNames(account.Pokemons); len(got) != 3 || got[0] != "Pikachu" || got[1] != "Bulbasaur" || got[2] != "Pikachu" {
+		t.Fatalf("saved roster = %v, want [Pikachu Bulbasaur Pikachu]", got)
+	}
+
+	roster, err = store.RemovePokemon("Ash", 1)
+	if err != nil {
+		t.Fatalf("RemovePokemon: %v", err)
+	}
+	if got := rosterNames(roster); len(got) != 2 || got[0] != "Bulbasaur" || got[1] != "Pikachu" || roster[1].RosterID != 3 {
+		t.Errorf("roster after removing the first Pikachu = %v", got)
+	}
+	if _, err := store.RemovePokemon("Ash", 1); err == nil {
+		t.Errorf("removing the first Pikachu twice succeeded")
+	}
+
+	store = openStore(t, path)
+	account, _ = store.Authenticate("Ash", "pikachu")
+	if got := rosterNames(account.Pokemons); len(got) != 2 || got[0] != "Bulbasaur" || got[1] != "Pikachu" {
+		t.Errorf("saved roster after removal = %v, want [Bulbasaur Pikachu]", got)
+	}
+}
+
+func TestPlayerStoreRosterIDsAreNotReused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.json")
+	store := openStore(t, path)
+	if _, err := store.Register("Ash", "pikachu"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	pikachu := Pokemon{Pokemon: pokedata.Pokemon{ID: "25", NationalID: 25, Name: "Pikachu"}}
+	store.AddPokemon("Ash", pikachu)
+	store.AddPokemon("Ash", pikachu)
+	if _, err := store.RemovePokemon("Ash", 2); err != nil {
+		t.Fatalf("RemovePokemon: %v", err)
+	}
+
+	// The counter is saved, so not even a reopened store gives out 2 again.
+	store = openStore(t, path)
+	roster, err := store.AddPokemon("Ash", pikachu)
+	if err != nil {
+		t.Fatalf("AddPokemon: %v", err)
+	}
+	if id := roster[len(roster)-1].RosterID; id != 3 {
+		t.Errorf("roster ID after removing 2 = %d, want 3", id)
+	}
+}
+
+// oldPlayers is a store saved before passwords were required and before
+// roster IDs existed.
+const oldPlayers = `[
+  {"name": "Misty", "pokemons": [
+    {"national_id": 120, "name": "Staryu"},
+    {"roster_id": 4, "national_id": 121, "name": "Starmie"},
+    {"national_id": 120, "name": "Staryu"}
+  ]}
+]`
+
+func TestOpenPlayerStoreUpgrades(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.json")
+	if err := os.WriteFile(path, []byte(oldPlayers), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := openStore(t, path)
+	if _, err := store.Authenticate("Misty", ""); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("Authenticate without a password: err = %v, want ErrPasswordRequired", err)
+	}
+
+	// Opening the store saved the roster IDs it assigned and the counter.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved []Account
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 || saved[0].NextRosterID != 7 || saved[0].Pokemons[0].RosterID != 5 {
+		t.Errorf("saved accounts = %+v, want roster IDs up to 6", saved)
+	}
+
+	for i := 0; i < 2; i++ {
+		store := openStore(t, path)
+		account, err := store.Authenticate("Misty", "starmie")
+		if err != nil {
+			t.Fatalf("Authenticate: %v", err)
+		}
+		var ids []int
+		for _, pokemon := range account.Pokemons {
+			ids = append(ids, pokemon.RosterID)
+		}
+		if len(ids) != 3 || ids[0] != 5 || ids[1] != 4 || ids[2] != 6 {
+			t.Errorf("roster IDs = %v, want [5 4 6]", ids)
+		}
+	}
+
+	// The first password used became the account's.
+	store = openStore(t, path)
+	if _, err := store.Authenticate("Misty", "staryu"); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("Authenticate with another password: err = %v, want ErrBadPassword", err)
+	}
+}
+
+func TestOpenPlayerStoreMissingFile(t *testing.T) {
+	store := openStore(t, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := store.Authenticate("Ash", "pikachu"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Authenticate on an empty store: err = %v", err)
+	}
+}
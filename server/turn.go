@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
+)
+
+// Action is what a player submitted for the current round.
+type Action struct {
+	Player *Player
+	Kind   string
+	// Slot is the moveset slot for move actions, -1 meaning Struggle.
+	Slot int
+	// Active is the Pokémon that was out when the action was chosen, so a move
+	// is dropped if that Pokémon faints before it gets to act.
+	Active int
+}
+
+// parseAction validates an action a player submitted for this round.
+func parseAction(player *Player, submitted protocol.Action) (Action, error) {
+	action := Action{Player: player, Kind: submitted.Kind, Active: player.Active[0]}
+
+	switch submitted.Kind {
+	case protocol.ActionMove:
+		slot, err := selectMove(player, submitted)
+		if err != nil {
+			return action, err
+		}
+		action.Slot = slot
+	case protocol.ActionSwitch:
+		if len(player.Active) < 2 {
+			return action, errors.New("No other Pokémon to switch to.")
+		}
+	case protocol.ActionForfeit:
+	default:
+		return action, fmt.Errorf("Unknown action: %q", submitted.Kind)
+	}
+	return action, nil
+}
+
+// selectMove resolves a move chosen by slot number or by name. A Pokémon with
+// no PP left in any move falls back to Struggle, reported as -1.
+func selectMove(player *Player, submitted protocol.Action) (int, error) {
+	moveset := player.Moves[0]
+	if !hasPP(moveset) {
+		return -1, nil
+	}
+
+	var slot int
+	if submitted.MoveName != "" {
+		slot = findMoveSlot(moveset, submitted.MoveName)
+		if slot < 0 {
+			var known []string
+			for _, moveSlot := range moveset {
+				known = append(known, moveSlot.Move.Name)
+			}
+			suggestions := pokedata.Suggest(known, submitted.MoveName, 2)
+			return 0, fmt.Errorf("Your Pokémon doesn't know %s.%s", submitted.MoveName, didYouMean(suggestions))
+		}
+	} else {
+		if submitted.Move < 1 || submitted.Move > len(moveset) {
+			return 0, fmt.Errorf("Invalid move number: %d", submitted.Move)
+		}
+		slot = submitted.Move - 1
+	}
+
+	if moveset[slot].PP <= 0 {
+		return 0, fmt.Errorf("%s has no PP left.", moveset[slot].Move.Name)
+	}
+	return slot, nil
+}
+
+// priority ranks an action within the round: forfeits and switches happen
+// before any move, and moves use their own priority bracket.
+func (a Action) priority() int {
+	switch a.Kind {
+	case protocol.ActionForfeit:
+		return 8
+	case protocol.ActionSwitch:
+		return 7
+	}
+	if a.Slot < 0 {
+		return 0
+	}
+	return a.Player.Moves[0][a.Slot].Move.Priority
+}
+
+// orderActions sorts the round's actions by priority, then by the speed of the
+// acting Pokémon, breaking speed ties at random.
+func orderActions(actions []Action) {
+	tiebreak := make(map[*Player]int, len(actions))
+	for _, action := range actions {
+		tiebreak[action.Player] = rand.Int()
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		pi, pj := actions[i].priority(), actions[j].priority()
+		if pi != pj {
+			return pi > pj
+		}
+		si, sj := getSpeedOfFirstActivePokemon(actions[i].Player), getSpeedOfFirstActivePokemon(actions[j].Player)
+		if si != sj {
+			return si > sj
+		}
+		return tiebreak[actions[i].Player] < tiebreak[actions[j].Player]
+	})
+}
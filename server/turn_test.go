@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"Pokemon/pokedata"
+	"Pokemon/protocol"
+)
+
+// battler is a player with one testPokemon out, with the given Speed and
+// status, that knows a single move of the given priority.
+func battler(name string, speed, priority int, status Status) *Player {
+	pokemon := testPokemon(1, "normal")
+	pokemon.RosterID = 1
+	pokemon.Speed = speed
+	move := testMove("move", "normal", pokedata.DamageClassPhysical, 40)
+	move.Priority = priority
+	return &Player{
+		Name:     name,
+		Pokemons: []Pokemon{*pokemon},
+		Active:   []int{1},
+		Moves:    [][]MoveSlot{{{Move: move, PP: 10, MaxPP: 10}}},
+		Status:   []StatusCondition{{Status: status}},
+	}
+}
+
+func TestOrderActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		slow  *Player
+		fast  *Player
+		kinds [2]string
+		// first is the name of the player who should act first.
+		first string
+	}{
+		{"faster moves first", battler("slow", 50, 0, ""), battler("fast", 100, 0, ""),
+			[2]string{protocol.ActionMove, protocol.ActionMove}, "fast"},
+		{"priority beats speed", battler("slow", 50, 1, ""), battler("fast", 100, 0, ""),
+			[2]string{protocol.ActionMove, protocol.ActionMove}, "slow"},
+		{"higher priority bracket", battler("slow", 50, 2, ""), battler("fast", 100, 1, ""),
+			[2]string{protocol.ActionMove, protocol.ActionMove}, "slow"},
+		{"negative priority moves last", battler("slow", 50, 0, ""), battler("fast", 100, -6, ""),
+			[2]string{protocol.ActionMove, protocol.ActionMove}, "slow"},
+		{"switch beats priority", battler("slow", 50, 0, ""), battler("fast", 100, 5, ""),
+			[2]string{protocol.ActionSwitch, protocol.ActionMove}, "slow"},
+		{"paralysis quarters speed", battler("slow", 50, 0, ""), battler("fast", 100, 0, StatusParalysis),
+			[2]string{protocol.ActionMove, protocol.ActionMove}, "slow"},
+		{"forfeit beats switch", battler("slow", 50, 0, ""), battler("fast", 100, 0, ""),
+			[2]string{protocol.ActionForfeit, protocol.ActionSwitch}, "slow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Order the actions both ways round so the result can't come from
+			// the order they were submitted in.
+			for _, swap := range []bool{false, true} {
+				actions := []Action{
+					{Player: tt.slow, Kind: tt.kinds[0], Active: 1},
+					{Player: tt.fast, Kind: tt.kinds[1], Active: 1},
+				}
+				if swap {
+					actions[0], actions[1] = actions[1], actions[0]
+				}
+				orderActions(actions)
+				if got := actions[0].Player.Name; got != tt.first {
+					t.Errorf("swapped %v: %s acted first, want %s", swap, got, tt.first)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderActionsSpeedTie(t *testing.T) {
+	// Ties are broken at random, so each player should go first sometimes.
+	first := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		actions := []Action{
+			{Player: battler("a", 80, 0, ""), Kind: protocol.ActionMove, Active: 1},
+			{Player: battler("b", 80, 0, ""), Kind: protocol.ActionMove, Active: 1},
+		}
+		orderActions(actions)
+		first[actions[0].Player.Name]++
+	}
+	if first["a"] == 0 || first["b"] == 0 {
+		t.Errorf("speed ties always went the same way: %v", first)
+	}
+}
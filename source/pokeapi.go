@@ -0,0 +1,677 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"Pokemon/fetch"
+	"Pokemon/pokedata"
+)
+
+// DefaultPokeAPIURL is the public PokéAPI.
+const DefaultPokeAPIURL = "https://pokeapi.co/api/v2"
+
+// PokeAPI reads a REST API laid out like PokéAPI v2: /pokemon-species/,
+// /pokemon/{id}/, /evolution-chain/{id}/, /move/{id}/ and /type/{name}/.
+// It covers every species the API knows, so it isn't limited to the first
+// 649.
+type PokeAPI struct {
+	fetcher *fetch.Fetcher
+	baseURL string
+	// limit is how many species to read, or 0 for all of them.
+	limit int
+	// versionGroup picks the learnsets of one version group, such as
+	// "black-white". When empty the most recent one with moves is used for
+	// each species.
+	versionGroup string
+
+	mu      sync.Mutex
+	pokemon []apiPokemon
+	species []apiSpecies
+}
+
+// NewPokeAPI returns a PokéAPI source rooted at baseURL, downloading with f.
+func NewPokeAPI(f *fetch.Fetcher, baseURL string, limit int, versionGroup string) *PokeAPI {
+	return &PokeAPI{
+		fetcher:      f,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		limit:        limit,
+		versionGroup: versionGroup,
+	}
+}
+
+func (s *PokeAPI) Name() string {
+	return "pokeapi"
+}
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type resourceList struct {
+	Count   int             `json:"count"`
+	Results []namedResource `json:"results"`
+}
+
+type apiName struct {
+	Name     string        `json:"name"`
+	Language namedResource `json:"language"`
+}
+
+type apiPokemon struct {
+	ID             int `json:"id"`
+	Name           string
+	Height         int `json:"height"`
+	Weight         int `json:"weight"`
+	BaseExperience int `json:"base_experience"`
+	Types          []struct {
+		Slot int           `json:"slot"`
+		Type namedResource `json:"type"`
+	} `json:"types"`
+	Abilities []struct {
+		Ability namedResource `json:"ability"`
+	} `json:"abilities"`
+	Stats []struct {
+		BaseStat int           `json:"base_stat"`
+		Effort   int           `json:"effort"`
+		Stat     namedResource `json:"stat"`
+	} `json:"stats"`
+	Moves []struct {
+		Move                namedResource `json:"move"`
+		VersionGroupDetails []struct {
+			LevelLearnedAt  int           `json:"level_learned_at"`
+			MoveLearnMethod namedResource `json:"move_learn_method"`
+			VersionGroup    namedResource `json:"version_group"`
+		} `json:"version_group_details"`
+	} `json:"moves"`
+}
+
+type apiSpecies struct {
+	ID           int             `json:"id"`
+	Name         string          `json:"name"`
+	CaptureRate  int             `json:"capture_rate"`
+	GenderRate   int             `json:"gender_rate"`
+	HatchCounter int             `json:"hatch_counter"`
+	EggGroups    []namedResource `json:"egg_groups"`
+	Genera       []struct {
+		Genus    string        `json:"genus"`
+		Language namedResource `json:"language"`
+	} `json:"genera"`
+	Names             []apiName `json:"names"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   namedResource `json:"language"`
+		Version    namedResource `json:"version"`
+	} `json:"flavor_text_entries"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+}
+
+type apiChainLink struct {
+	Species          namedResource `json:"species"`
+	EvolutionDetails []struct {
+		MinLevel *int           `json:"min_level"`
+		Trigger  namedResource  `json:"trigger"`
+		Item     *namedResource `json:"item"`
+	} `json:"evolution_details"`
+	EvolvesTo []apiChainLink `json:"evolves_to"`
+}
+
+type apiMove struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	Type         namedResource `json:"type"`
+	Power        *int          `json:"power"`
+	PP           *int          `json:"pp"`
+	Accuracy     *int          `json:"accuracy"`
+	Priority     int           `json:"priority"`
+	DamageClass  namedResource `json:"damage_class"`
+	EffectChance *int          `json:"effect_chance"`
+	Effects      []struct {
+		Effect   string        `json:"effect"`
+		Language namedResource `json:"language"`
+	} `json:"effect_entries"`
+}
+
+type apiType struct {
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageFrom []namedResource `json:"double_damage_from"`
+		HalfDamageFrom   []namedResource `json:"half_damage_from"`
+		NoDamageFrom     []namedResource `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+// getAll downloads several resources and decodes each into the matching
+// element of out, which must be a slice of the same length.
+func getAll[T any](ctx context.Context, f *fetch.Fetcher, urls []string) ([]T, error) {
+	pages, err := f.GetAll(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(pages))
+	for i, page := range pages {
+		if err := json.Unmarshal(page, &out[i]); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", urls[i], err)
+		}
+	}
+	return out, nil
+}
+
+// list returns the URLs of every resource of a kind, up to limit if it's
+// positive.
+func (s *PokeAPI) list(ctx context.Context, kind string, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 100000
+	}
+	lists, err := getAll[resourceList](ctx, s.fetcher, []string{fmt.Sprintf("%s/%s/?limit=%d", s.baseURL, kind, limit)})
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, resource := range lists[0].Results {
+		urls = append(urls, resource.URL)
+	}
+	return urls, nil
+}
+
+// load downloads the species and their default forms once, as most files
+// need both.
+func (s *PokeAPI) load(ctx context.Context) ([]apiPokemon, []apiSpecies, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.species != nil {
+		return s.pokemon, s.species, nil
+	}
+
+	speciesURLs, err := s.list(ctx, "pokemon-species", s.limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	species, err := getAll[apiSpecies](ctx, s.fetcher, speciesURLs)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(species, func(i, j int) bool { return species[i].ID < species[j].ID })
+
+	pokemonURLs := make([]string, len(species))
+	for i, sp := range species {
+		pokemonURLs[i] = fmt.Sprintf("%s/pokemon/%d/", s.baseURL, sp.ID)
+	}
+	pokemon, err := getAll[apiPokemon](ctx, s.fetcher, pokemonURLs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.pokemon, s.species = pokemon, species
+	return pokemon, species, nil
+}
+
+func (s *PokeAPI) Species(ctx context.Context) ([]pokedata.Pokemon, error) {
+	pokemon, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	descriptions := describe(species)
+
+	var out []pokedata.Pokemon
+	for i, p := range pokemon {
+		sp := species[i]
+		record := pokedata.Pokemon{
+			Descriptions: descriptions[i].refs,
+			Weight:       strconv.Itoa(p.Weight),
+			Height:       strconv.Itoa(p.Height),
+			NationalID:   sp.ID,
+			CatchRate:    sp.CaptureRate,
+			ID:           strconv.Itoa(sp.ID),
+			Name:         englishName(sp.Names, sp.Name),
+		}
+		if sp.GenderRate >= 0 {
+			male := float64(8-sp.GenderRate) * 12.5
+			record.MaleFemaleRatio = fmt.Sprintf("%g/%g", male, 100-male)
+		}
+		types := p.Types
+		sort.Slice(types, func(i, j int) bool { return types[i].Slot < types[j].Slot })
+		for _, t := range types {
+			record.Types = append(record.Types, pokedata.ListMapObject{Name: t.Type.Name, ResourceURI: t.Type.URL})
+		}
+		for _, a := range p.Abilities {
+			record.Abilities = append(record.Abilities, pokedata.ListMapObject{Name: a.Ability.Name, ResourceURI: a.Ability.URL})
+		}
+		for _, stat := range p.Stats {
+			switch stat.Stat.Name {
+			case "hp":
+				record.HP = stat.BaseStat
+			case "attack":
+				record.Attack = stat.BaseStat
+			case "defense":
+				record.Defense = stat.BaseStat
+			case "special-attack":
+				record.SpAtk = stat.BaseStat
+			case "special-defense":
+				record.SpDef = stat.BaseStat
+			case "speed":
+				record.Speed = stat.BaseStat
+			}
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+// eggGroupNames are the names pokedex.org gives PokéAPI's egg groups.
+var eggGroupNames = map[string]string{
+	"monster":       "Monster",
+	"water1":        "Water 1",
+	"water2":        "Water 2",
+	"water3":        "Water 3",
+	"bug":           "Bug",
+	"flying":        "Flying",
+	"ground":        "Field",
+	"fairy":         "Fairy",
+	"plant":         "Grass",
+	"humanshape":    "Human-Like",
+	"mineral":       "Mineral",
+	"indeterminate": "Amorphous",
+	"ditto":         "Ditto",
+	"dragon":        "Dragon",
+	"no-eggs":       "No Eggs",
+}
+
+func (s *PokeAPI) Supplemental(ctx context.Context) ([]pokedata.AdditionalInfo, error) {
+	pokemon, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []pokedata.AdditionalInfo
+	for i, p := range pokemon {
+		sp := species[i]
+		record := pokedata.AdditionalInfo{
+			HatchSteps: sp.HatchCounter * 255,
+			ID:         fmt.Sprintf("%05d", sp.ID),
+		}
+		for _, stat := range p.Stats {
+			switch stat.Stat.Name {
+			case "hp":
+				record.HPEV = stat.Effort
+			case "attack":
+				record.AttackEV = stat.Effort
+			case "defense":
+				record.DefenseEV = stat.Effort
+			case "special-attack":
+				record.SpecialAttackEV = stat.Effort
+			case "special-defense":
+				record.SpecialDefenseEV = stat.Effort
+			case "speed":
+				record.SpeedEV = stat.Effort
+			}
+		}
+		if sp.GenderRate < 0 {
+			record.GenderRatio = "N/A"
+		} else {
+			record.GenderRatio = float64(8-sp.GenderRate) * 12.5
+		}
+		for _, genus := range sp.Genera {
+			if genus.Language.Name == "en" {
+				record.Species = genus.Genus
+			}
+		}
+		for _, name := range sp.Names {
+			switch name.Language.Name {
+			case "ja-Hrkt":
+				record.JapaneseName = name.Name
+			case "roomaji":
+				record.HepburnName = name.Name
+			}
+		}
+		var groups []string
+		for _, group := range sp.EggGroups {
+			if name, ok := eggGroupNames[group.Name]; ok {
+				groups = append(groups, name)
+			} else {
+				groups = append(groups, titleSlug(group.Name))
+			}
+		}
+		record.EggGroups = strings.Join(groups, ", ")
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+type speciesDescriptions struct {
+	refs    []pokedata.ListMapObject
+	entries []pokedata.Description
+}
+
+// describe numbers the English flavor texts of every species, one per game
+// version, and links each species to its own the way pokedex.org does, by a
+// resource URI ending in the description's number.
+func describe(species []apiSpecies) []speciesDescriptions {
+	out := make([]speciesDescriptions, len(species))
+	next := 1
+	for i, sp := range species {
+		for _, entry := range sp.FlavorTextEntries {
+			if entry.Language.Name != "en" {
+				continue
+			}
+			out[i].refs = append(out[i].refs, pokedata.ListMapObject{
+				Name:        fmt.Sprintf("%s_%s", sp.Name, strings.ReplaceAll(entry.Version.Name, "-", "_")),
+				ResourceURI: fmt.Sprintf("/api/v1/description/%d/", next),
+			})
+			out[i].entries = append(out[i].entries, pokedata.Description{
+				Description: strings.Join(strings.Fields(strings.NewReplacer("\f", " ", "­", "").Replace(entry.FlavorText)), " "),
+				ID:          fmt.Sprintf("%07d", next),
+			})
+			next++
+		}
+	}
+	return out
+}
+
+func (s *PokeAPI) Descriptions(ctx context.Context) ([]pokedata.Description, error) {
+	_, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := []pokedata.Description{}
+	for _, d := range describe(species) {
+		out = append(out, d.entries...)
+	}
+	return out, nil
+}
+
+func (s *PokeAPI) Evolutions(ctx context.Context) ([]pokedata.Evolution, error) {
+	_, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var chainURLs []string
+	seen := make(map[string]bool)
+	for _, sp := range species {
+		if url := sp.EvolutionChain.URL; url != "" && !seen[url] {
+			seen[url] = true
+			chainURLs = append(chainURLs, url)
+		}
+	}
+	chains, err := getAll[struct {
+		Chain apiChainLink `json:"chain"`
+	}](ctx, s.fetcher, chainURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string)
+	for _, sp := range species {
+		names[sp.ID] = englishName(sp.Names, sp.Name)
+	}
+	from := make(map[int][]pokedata.EvolutionDetail)
+	to := make(map[int][]pokedata.EvolutionDetail)
+	var walk func(link apiChainLink)
+	walk = func(link apiChainLink) {
+		parent := resourceID(link.Species.URL)
+		for _, child := range link.EvolvesTo {
+			id := resourceID(child.Species.URL)
+			method, level := evolutionMethod(child)
+			to[parent] = append(to[parent], pokedata.EvolutionDetail{NationalID: id, Name: speciesName(names, id, child.Species.Name), Method: method, Level: level})
+			from[id] = append(from[id], pokedata.EvolutionDetail{NationalID: parent, Name: speciesName(names, parent, link.Species.Name), Method: method, Level: level})
+			walk(child)
+		}
+	}
+	for _, chain := range chains {
+		walk(chain.Chain)
+	}
+
+	var out []pokedata.Evolution
+	for _, sp := range species {
+		out = append(out, pokedata.Evolution{From: from[sp.ID], To: to[sp.ID], ID: fmt.Sprintf("%05d", sp.ID)})
+	}
+	return out, nil
+}
+
+// evolutionMethod names how a species evolves the way pokedex.org does.
+func evolutionMethod(link apiChainLink) (string, int) {
+	if len(link.EvolutionDetails) == 0 {
+		return "other", 0
+	}
+	detail := link.EvolutionDetails[0]
+	level := 0
+	if detail.MinLevel != nil {
+		level = *detail.MinLevel
+	}
+	switch detail.Trigger.Name {
+	case "level-up":
+		return "level_up", level
+	case "trade":
+		return "trade", level
+	case "use-item":
+		if detail.Item != nil && strings.HasSuffix(detail.Item.Name, "-stone") {
+			return "stone", level
+		}
+	}
+	return "other", level
+}
+
+// learnMethods are the names pokedex.org gives PokéAPI's learn methods.
+var learnMethods = map[string]string{
+	"level-up": "level up",
+	"machine":  "machine",
+	"tutor":    "tutor",
+	"egg":      "egg move",
+}
+
+func (s *PokeAPI) Learnsets(ctx context.Context) ([]pokedata.MonsterMoves, error) {
+	pokemon, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []pokedata.MonsterMoves
+	for i, p := range pokemon {
+		versionGroup := s.versionGroup
+		if versionGroup == "" {
+			newest := 0
+			for _, move := range p.Moves {
+				for _, detail := range move.VersionGroupDetails {
+					if id := resourceID(detail.VersionGroup.URL); id > newest {
+						newest, versionGroup = id, detail.VersionGroup.Name
+					}
+				}
+			}
+		}
+
+		record := pokedata.MonsterMoves{Moves: []pokedata.LearnedMove{}, ID: fmt.Sprintf("%05d", species[i].ID)}
+		for _, move := range p.Moves {
+			for _, detail := range move.VersionGroupDetails {
+				if detail.VersionGroup.Name != versionGroup {
+					continue
+				}
+				method, ok := learnMethods[detail.MoveLearnMethod.Name]
+				if !ok {
+					method = "other"
+				}
+				record.Moves = append(record.Moves, pokedata.LearnedMove{
+					LearnType: method,
+					Level:     detail.LevelLearnedAt,
+					ID:        resourceID(move.Move.URL),
+				})
+			}
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+func (s *PokeAPI) Moves(ctx context.Context) ([]pokedata.MoveRecord, error) {
+	urls, err := s.list(ctx, "move", 0)
+	if err != nil {
+		return nil, err
+	}
+	moves, err := getAll[apiMove](ctx, s.fetcher, urls)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].ID < moves[j].ID })
+
+	var out []pokedata.MoveRecord
+	for _, move := range moves {
+		priority := move.Priority
+		record := pokedata.MoveRecord{
+			TypeName:    move.Type.Name,
+			Identifier:  move.Name,
+			Power:       recordValue(move.Power),
+			PP:          recordValue(move.PP),
+			Accuracy:    recordValue(move.Accuracy),
+			Name:        titleSlug(move.Name),
+			Priority:    &priority,
+			DamageClass: pokedata.DamageClass(move.DamageClass.Name),
+			ID:          fmt.Sprintf("%05d", move.ID),
+		}
+		for _, effect := range move.Effects {
+			if effect.Language.Name == "en" {
+				record.Description = effect.Effect
+				if move.EffectChance != nil {
+					record.Description = strings.ReplaceAll(record.Description, "$effect_chance", strconv.Itoa(*move.EffectChance))
+				}
+			}
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+// recordValue stores a missing number the way moves.json does, as an empty
+// string.
+func recordValue(value *int) interface{} {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// TypeMultipliers works out each species' multipliers from the damage
+// relations of its types, listing only those that aren't neutral. It replaces
+// the pokedex.org scrape, which got the same page for every species.
+func (s *PokeAPI) TypeMultipliers(ctx context.Context) ([]pokedata.Mult, error) {
+	pokemon, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeURLs []string
+	seen := make(map[string]bool)
+	for _, p := range pokemon {
+		for _, t := range p.Types {
+			if !seen[t.Type.URL] {
+				seen[t.Type.URL] = true
+				typeURLs = append(typeURLs, t.Type.URL)
+			}
+		}
+	}
+	types, err := getAll[apiType](ctx, s.fetcher, typeURLs)
+	if err != nil {
+		return nil, err
+	}
+	relations := make(map[string]map[string]float64)
+	for _, t := range types {
+		damage := make(map[string]float64)
+		for _, r := range t.DamageRelations.DoubleDamageFrom {
+			damage[r.Name] = 2
+		}
+		for _, r := range t.DamageRelations.HalfDamageFrom {
+			damage[r.Name] = 0.5
+		}
+		for _, r := range t.DamageRelations.NoDamageFrom {
+			damage[r.Name] = 0
+		}
+		relations[t.Name] = damage
+	}
+
+	var out []pokedata.Mult
+	for i, p := range pokemon {
+		multipliers := make(map[string]float64)
+		for _, t := range p.Types {
+			for attackType, value := range relations[t.Type.Name] {
+				if current, ok := multipliers[attackType]; ok {
+					value *= current
+				}
+				multipliers[attackType] = value
+			}
+		}
+		var attackTypes []string
+		for attackType := range multipliers {
+			attackTypes = append(attackTypes, attackType)
+		}
+		sort.Strings(attackTypes)
+
+		mult := pokedata.Mult{ID: species[i].ID, MonsterTypes: []pokedata.MonsterType{}}
+		for _, attackType := range attackTypes {
+			if value := multipliers[attackType]; value != 1 {
+				mult.MonsterTypes = append(mult.MonsterTypes, pokedata.MonsterType{
+					Type:       attackType,
+					Multiplier: strconv.FormatFloat(value, 'g', -1, 64) + "x",
+				})
+			}
+		}
+		out = append(out, mult)
+	}
+	return out, nil
+}
+
+func (s *PokeAPI) Experience(ctx context.Context) ([]pokedata.Experience, error) {
+	pokemon, species, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []pokedata.Experience
+	for i, p := range pokemon {
+		sp := species[i]
+		out = append(out, pokedata.Experience{
+			ID:   fmt.Sprintf("%04d", sp.ID),
+			Name: englishName(sp.Names, sp.Name),
+			Exp:  strconv.Itoa(p.BaseExperience),
+		})
+	}
+	return out, nil
+}
+
+// resourceID reads the ID at the end of a resource URL such as
+// "https://pokeapi.co/api/v2/pokemon-species/1/".
+func resourceID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, _ := strconv.Atoi(parts[len(parts)-1])
+	return id
+}
+
+func englishName(names []apiName, slug string) string {
+	for _, name := range names {
+		if name.Language.Name == "en" {
+			return name.Name
+		}
+	}
+	return titleSlug(slug)
+}
+
+func speciesName(names map[int]string, id int, slug string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return titleSlug(slug)
+}
+
+// titleSlug capitalizes an identifier such as "karate-chop" the way
+// pokedex.org names moves, as "Karate-chop".
+func titleSlug(slug string) string {
+	if slug == "" {
+		return ""
+	}
+	return strings.ToUpper(slug[:1]) + slug[1:]
+}
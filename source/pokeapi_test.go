@@ -0,0 +1,230 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Pokemon/fetch"
+	"Pokemon/pokedata"
+)
+
+// newFixtureAPI serves the responses recorded in testdata/pokeapi, trimmed to
+// Bulbasaur and Chespin, in place of pokeapi.co. The fixtures keep the
+// absolute pokeapi.co URLs the API links with, so they are rewritten to the
+// test server too.
+func newFixtureAPI(t *testing.T, versionGroup string) *PokeAPI {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/")
+		data, err := os.ReadFile(filepath.Join("testdata", "pokeapi", filepath.FromSlash(name)+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	f := fetch.New(fetch.Config{Hosts: map[string]string{"pokeapi.co": server.URL}})
+	return NewPokeAPI(f, DefaultPokeAPIURL, 0, versionGroup)
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func check[T any](t *testing.T, name string, got []T, err error, want []T) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d records, want %d: %+v", name, len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("%s[%d]:\n got %+v\nwant %+v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestPokeAPISpecies(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.Species(context.Background())
+	check(t, "Species", got, err, []pokedata.Pokemon{
+		{
+			Descriptions: []pokedata.ListMapObject{
+				{Name: "bulbasaur_red", ResourceURI: "/api/v1/description/1/"},
+				{Name: "bulbasaur_alpha_sapphire", ResourceURI: "/api/v1/description/2/"},
+			},
+			Types: []pokedata.ListMapObject{
+				{Name: "grass", ResourceURI: "https://pokeapi.co/api/v2/type/12/"},
+				{Name: "poison", ResourceURI: "https://pokeapi.co/api/v2/type/4/"},
+			},
+			Abilities: []pokedata.ListMapObject{
+				{Name: "overgrow", ResourceURI: "https://pokeapi.co/api/v2/ability/65/"},
+				{Name: "chlorophyll", ResourceURI: "https://pokeapi.co/api/v2/ability/34/"},
+			},
+			Attack: 49, Defense: 49, Speed: 45, SpAtk: 65, SpDef: 65, HP: 45,
+			Weight: "69", Height: "7",
+			NationalID: 1, MaleFemaleRatio: "87.5/12.5", CatchRate: 45,
+			ID: "1", Name: "Bulbasaur",
+		},
+		{
+			Descriptions: []pokedata.ListMapObject{
+				{Name: "chespin_x", ResourceURI: "/api/v1/description/3/"},
+			},
+			Types: []pokedata.ListMapObject{
+				{Name: "grass", ResourceURI: "https://pokeapi.co/api/v2/type/12/"},
+			},
+			Abilities: []pokedata.ListMapObject{
+				{Name: "overgrow", ResourceURI: "https://pokeapi.co/api/v2/ability/65/"},
+			},
+			Attack: 61, Defense: 65, Speed: 38, SpAtk: 48, SpDef: 45, HP: 56,
+			Weight: "90", Height: "4",
+			NationalID: 650, MaleFemaleRatio: "87.5/12.5", CatchRate: 45,
+			ID: "650", Name: "Chespin",
+		},
+	})
+}
+
+func TestPokeAPISupplemental(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.Supplemental(context.Background())
+	check(t, "Supplemental", got, err, []pokedata.AdditionalInfo{
+		{
+			SpecialAttackEV: 1, HatchSteps: 5100, GenderRatio: 87.5,
+			Species: "Seed Pokémon", JapaneseName: "フシギダネ", HepburnName: "Fushigidane",
+			EggGroups: "Monster, Grass", ID: "00001",
+		},
+		{
+			DefenseEV: 1, HatchSteps: 5100, GenderRatio: 87.5,
+			Species: "Spiny Nut Pokémon", JapaneseName: "ハリマロン", HepburnName: "Harimaron",
+			EggGroups: "Field", ID: "00650",
+		},
+	})
+}
+
+func TestPokeAPIDescriptions(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.Descriptions(context.Background())
+	check(t, "Descriptions", got, err, []pokedata.Description{
+		{Description: "A strange seed was planted on its back at birth. The plant sprouts and grows with this POKéMON.", ID: "0000001"},
+		{Description: "While it is young, it uses the nutrients that are stored in the seed on its back in order to grow.", ID: "0000002"},
+		{Description: "The quills on its head are usually soft.", ID: "0000003"},
+	})
+}
+
+func TestPokeAPIEvolutions(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.Evolutions(context.Background())
+	check(t, "Evolutions", got, err, []pokedata.Evolution{
+		{To: []pokedata.EvolutionDetail{{NationalID: 2, Name: "Ivysaur", Method: "level_up", Level: 16}}, ID: "00001"},
+		{To: []pokedata.EvolutionDetail{{NationalID: 651, Name: "Quilladin", Method: "level_up", Level: 16}}, ID: "00650"},
+	})
+}
+
+func TestPokeAPILearnsets(t *testing.T) {
+	tests := []struct {
+		versionGroup string
+		want         []pokedata.MonsterMoves
+	}{
+		// The newest version group each species has moves in.
+		{"", []pokedata.MonsterMoves{
+			{Moves: []pokedata.LearnedMove{
+				{LearnType: "machine", ID: 14},
+				{LearnType: "level up", Level: 1, ID: 33},
+				{LearnType: "tutor", ID: 98},
+			}, ID: "00001"},
+			{Moves: []pokedata.LearnedMove{
+				{LearnType: "level up", Level: 1, ID: 33},
+				{LearnType: "level up", Level: 8, ID: 98},
+			}, ID: "00650"},
+		}},
+		{"black-white", []pokedata.MonsterMoves{
+			{Moves: []pokedata.LearnedMove{
+				{LearnType: "machine", ID: 14},
+				{LearnType: "level up", Level: 1, ID: 33},
+				{LearnType: "egg move", ID: 52},
+			}, ID: "00001"},
+			{Moves: []pokedata.LearnedMove{}, ID: "00650"},
+		}},
+	}
+	for _, tt := range tests {
+		s := newFixtureAPI(t, tt.versionGroup)
+		got, err := s.Learnsets(context.Background())
+		check(t, "Learnsets "+tt.versionGroup, got, err, tt.want)
+	}
+}
+
+func TestPokeAPIMoves(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.Moves(context.Background())
+	check(t, "Moves", got, err, []pokedata.MoveRecord{
+		{
+			TypeName: "normal", Identifier: "swords-dance", Power: "", PP: 20, Accuracy: "",
+			Description: "Raises the user's Attack by two stages.", Name: "Swords-dance",
+			Priority: intPtr(0), DamageClass: pokedata.DamageClassStatus, ID: "00014",
+		},
+		{
+			TypeName: "normal", Identifier: "tackle", Power: 40, PP: 35, Accuracy: 100,
+			Description: "Inflicts regular damage with no additional effect.", Name: "Tackle",
+			Priority: intPtr(0), DamageClass: pokedata.DamageClassPhysical, ID: "00033",
+		},
+		{
+			TypeName: "fire", Identifier: "ember", Power: 40, PP: 25, Accuracy: 100,
+			Description: "Inflicts regular damage.  Has a 10% chance to burn the target.", Name: "Ember",
+			Priority: intPtr(0), DamageClass: pokedata.DamageClassSpecial, ID: "00052",
+		},
+		{
+			TypeName: "normal", Identifier: "quick-attack", Power: 40, PP: 30, Accuracy: 100,
+			Description: "Inflicts regular damage.  This move has +1 priority.", Name: "Quick-attack",
+			Priority: intPtr(1), DamageClass: pokedata.DamageClassPhysical, ID: "00098",
+		},
+	})
+}
+
+func TestPokeAPITypeMultipliers(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.TypeMultipliers(context.Background())
+	check(t, "TypeMultipliers", got, err, []pokedata.Mult{
+		{ID: 1, MonsterTypes: []pokedata.MonsterType{
+			{Type: "electric", Multiplier: "0.5x"},
+			{Type: "fairy", Multiplier: "0.5x"},
+			{Type: "fighting", Multiplier: "0.5x"},
+			{Type: "fire", Multiplier: "2x"},
+			{Type: "flying", Multiplier: "2x"},
+			{Type: "grass", Multiplier: "0.25x"},
+			{Type: "ice", Multiplier: "2x"},
+			{Type: "psychic", Multiplier: "2x"},
+			{Type: "water", Multiplier: "0.5x"},
+		}},
+		{ID: 650, MonsterTypes: []pokedata.MonsterType{
+			{Type: "bug", Multiplier: "2x"},
+			{Type: "electric", Multiplier: "0.5x"},
+			{Type: "fire", Multiplier: "2x"},
+			{Type: "flying", Multiplier: "2x"},
+			{Type: "grass", Multiplier: "0.5x"},
+			{Type: "ground", Multiplier: "0.5x"},
+			{Type: "ice", Multiplier: "2x"},
+			{Type: "poison", Multiplier: "2x"},
+			{Type: "water", Multiplier: "0.5x"},
+		}},
+	})
+}
+
+func TestPokeAPIExperience(t *testing.T) {
+	s := newFixtureAPI(t, "")
+	got, err := s.Experience(context.Background())
+	check(t, "Experience", got, err, []pokedata.Experience{
+		{ID: "0001", Name: "Bulbasaur", Exp: "64"},
+		{ID: "0650", Name: "Chespin", Exp: "63"},
+	})
+}
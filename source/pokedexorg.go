@@ -0,0 +1,165 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"Pokemon/fetch"
+	"Pokemon/pokedata"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PokedexOrg reads the CouchDB dumps pokedex.org serves to its web worker,
+// and a Bulbapedia table for the base experience yields. It only knows the
+// first 649 species and has no type multipliers; crawl MonsterType.json from
+// PokeAPI instead.
+type PokedexOrg struct {
+	fetcher *fetch.Fetcher
+}
+
+// NewPokedexOrg returns the pokedex.org source, downloading with f.
+func NewPokedexOrg(f *fetch.Fetcher) *PokedexOrg {
+	return &PokedexOrg{fetcher: f}
+}
+
+func (s *PokedexOrg) Name() string {
+	return "pokedex.org"
+}
+
+// skimDocs downloads the dump files with the given name and decodes the
+// documents in them. Each line of a dump holds a batch of documents; lines
+// that can't be read are logged and skipped.
+func skimDocs[T any](ctx context.Context, f *fetch.Fetcher, name string, parts int) ([]T, error) {
+	urls := []string{fmt.Sprintf("https://pokedex.org/assets/%s.txt", name)}
+	if parts > 0 {
+		urls = make([]string, parts)
+		for i := range urls {
+			urls[i] = fmt.Sprintf("https://pokedex.org/assets/%s-%d.txt", name, i+1)
+		}
+	}
+	pages, err := f.GetAll(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []T
+	for _, content := range pages {
+		for _, part := range strings.Split(string(content), "\n") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+
+			var inputData struct {
+				Docs []T `json:"docs"`
+				Seq  int `json:"seq"`
+			}
+			if err := json.Unmarshal([]byte(part), &inputData); err != nil {
+				log.Printf("Failed to unmarshal part: %s\nError: %s", part, err)
+				continue
+			}
+			docs = append(docs, inputData.Docs...)
+		}
+	}
+	return docs, nil
+}
+
+// Species drops the leading zeros of the species IDs.
+func (s *PokedexOrg) Species(ctx context.Context) ([]pokedata.Pokemon, error) {
+	docs, err := skimDocs[pokedata.Pokemon](ctx, s.fetcher, "skim-monsters", 3)
+	if err != nil {
+		return nil, err
+	}
+	var species []pokedata.Pokemon
+	for _, monster := range docs {
+		id, err := strconv.Atoi(monster.ID)
+		if err != nil {
+			log.Printf("Failed to convert monster ID to int value: %s\nError: %s", monster.ID, err)
+			continue
+		}
+		monster.ID = strconv.Itoa(id)
+		species = append(species, monster)
+	}
+	return species, nil
+}
+
+func (s *PokedexOrg) Supplemental(ctx context.Context) ([]pokedata.AdditionalInfo, error) {
+	return skimDocs[pokedata.AdditionalInfo](ctx, s.fetcher, "monsters-supplemental", 3)
+}
+
+func (s *PokedexOrg) Descriptions(ctx context.Context) ([]pokedata.Description, error) {
+	return skimDocs[pokedata.Description](ctx, s.fetcher, "descriptions", 3)
+}
+
+func (s *PokedexOrg) Evolutions(ctx context.Context) ([]pokedata.Evolution, error) {
+	return skimDocs[pokedata.Evolution](ctx, s.fetcher, "evolutions", 0)
+}
+
+func (s *PokedexOrg) Learnsets(ctx context.Context) ([]pokedata.MonsterMoves, error) {
+	return skimDocs[pokedata.MonsterMoves](ctx, s.fetcher, "monster-moves", 3)
+}
+
+func (s *PokedexOrg) Moves(ctx context.Context) ([]pokedata.MoveRecord, error) {
+	return skimDocs[pokedata.MoveRecord](ctx, s.fetcher, "moves", 3)
+}
+
+// TypeMultipliers isn't supported. pokedex.org picks the species of its
+// pages by the URL fragment, which is never sent to the server, so every
+// species page it serves is the same.
+func (s *PokedexOrg) TypeMultipliers(ctx context.Context) ([]pokedata.Mult, error) {
+	return nil, fmt.Errorf("pokedex.org has no per-species type multipliers: %w", errors.ErrUnsupported)
+}
+
+// expLimit is how many rows of the Bulbapedia table are read, which covers
+// the species pokedex.org knows.
+const expLimit = 682
+
+// Experience scrapes the base experience yields from Bulbapedia. The table
+// lists some species more than once, so only the first row for each is kept.
+func (s *PokedexOrg) Experience(ctx context.Context) ([]pokedata.Experience, error) {
+	page, err := s.fetcher.Get(ctx, "https://bulbapedia.bulbagarden.net/wiki/List_of_Pok%C3%A9mon_by_effort_value_yield_(Generation_IX)")
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("Error loading the document: %w", err)
+	}
+
+	count := 0
+	seen := make(map[string]bool)
+	baseExp := make([]pokedata.Experience, 0)
+	doc.Find("table.sortable").Each(func(i int, table *goquery.Selection) {
+		table.Find("tr").Each(func(i int, tr *goquery.Selection) {
+			var ID, Name, Exp string
+			tr.Find("td").Each(func(j int, td *goquery.Selection) {
+				if count < expLimit {
+					if j == 0 {
+						ID = strings.TrimSpace(td.Text())
+					}
+					if j == 2 {
+						Name = td.Find("a").AttrOr("title", "")
+						Name = strings.TrimSuffix(Name, " (Pokémon)")
+					}
+					if j == 3 {
+						Exp = strings.TrimSpace(td.Text())
+					}
+				}
+			})
+			if ID != "" && Name != "" && Exp != "" {
+				count++
+				if !seen[Name] {
+					seen[Name] = true
+					baseExp = append(baseExp, pokedata.Experience{ID: ID, Name: Name, Exp: Exp})
+				}
+			}
+		})
+	})
+	return baseExp, nil
+}
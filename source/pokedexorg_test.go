@@ -0,0 +1,15 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPokedexOrgTypeMultipliers(t *testing.T) {
+	// Nothing is downloaded, so no fetcher is needed.
+	got, err := NewPokedexOrg(nil).TypeMultipliers(context.Background())
+	if !errors.Is(err, errors.ErrUnsupported) || got != nil {
+		t.Errorf("TypeMultipliers = %v, %v, want errors.ErrUnsupported", got, err)
+	}
+}
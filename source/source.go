@@ -0,0 +1,34 @@
+// Package source reads Pokémon data from the sites the crawlers download it
+// from and normalizes it into the pokedata model, which is what the data
+// files hold.
+package source
+
+import (
+	"context"
+
+	"Pokemon/pokedata"
+)
+
+// Source is somewhere the crawlers can get every data file from.
+type Source interface {
+	// Name is what the crawler command selects the source by.
+	Name() string
+	// Species are the records of baseInfo.json.
+	Species(ctx context.Context) ([]pokedata.Pokemon, error)
+	// Supplemental are the records of stats.json.
+	Supplemental(ctx context.Context) ([]pokedata.AdditionalInfo, error)
+	// Descriptions are the records of MonsterDescription.json.
+	Descriptions(ctx context.Context) ([]pokedata.Description, error)
+	// Evolutions are the records of evolution.json.
+	Evolutions(ctx context.Context) ([]pokedata.Evolution, error)
+	// Learnsets are the records of monsterMoves.json.
+	Learnsets(ctx context.Context) ([]pokedata.MonsterMoves, error)
+	// Moves are the records of moves.json.
+	Moves(ctx context.Context) ([]pokedata.MoveRecord, error)
+	// TypeMultipliers are the records of MonsterType.json. Sources that
+	// can't provide them return an error wrapping errors.ErrUnsupported;
+	// only PokeAPI can.
+	TypeMultipliers(ctx context.Context) ([]pokedata.Mult, error)
+	// Experience are the records of exp.json.
+	Experience(ctx context.Context) ([]pokedata.Experience, error)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"Pokemon/pokedata"
+)
+
+func main() {
+	dataDir := flag.String("data", "../data", "directory containing the crawled data files")
+	jsonOut := flag.Bool("json", false, "print the problems as a JSON array")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: validate [-data dir] [-json]")
+		fmt.Fprintln(os.Stderr, "\nChecks the data files against each other and lists the problems found.")
+		fmt.Fprintln(os.Stderr, "Exits with status 1 if there are any.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	problems, err := pokedata.Validate(*dataDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read the data files:", err)
+		os.Exit(2)
+	}
+
+	if *jsonOut {
+		if problems == nil {
+			problems = []pokedata.Problem{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(problems); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else {
+		counts := make(map[string]int)
+		for _, problem := range problems {
+			fmt.Println(problem)
+			counts[problem.Check]++
+		}
+		fmt.Fprintf(os.Stderr, "%d problems", len(problems))
+		for _, check := range []string{pokedata.CheckSchema, pokedata.CheckID, pokedata.CheckDuplicate, pokedata.CheckDangling, pokedata.CheckMissing, pokedata.CheckTypeChart} {
+			if counts[check] > 0 {
+				fmt.Fprintf(os.Stderr, ", %d %s", counts[check], check)
+			}
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}